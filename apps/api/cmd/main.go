@@ -50,6 +50,19 @@ func run(log *slog.Logger, env string) error {
 	svc := cloudwatch.NewService(aws.Config{})
 	ib := metric.NewIncomingBytes(log.WithGroup("incoming_bytes"), nil)
 	svc.NewMetric(ib)
+
+	// Generic CloudWatch metrics defined in CLOUDWATCH_METRICS_PATH.
+	mCfgs, err := metric.ConfigsFromEnv()
+	if err != nil {
+		return fmt.Errorf("metric.ConfigsFromEnv: %w", err)
+	}
+	for _, mc := range mCfgs {
+		gm, err := metric.NewGeneric(log.WithGroup("cloudwatch_metric"), nil, mc)
+		if err != nil {
+			return fmt.Errorf("metric.NewGeneric: %w", err)
+		}
+		svc.NewMetric(gm)
+	}
 	costwatch.RegisterService(svc)
 
 	// CoinGecko provider with BTC/USD metrics (disabled when DEMO=false)
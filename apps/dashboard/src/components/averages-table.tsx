@@ -46,7 +46,10 @@ function AveragesTableComponent({ data }: AveragesTableProps) {
 			<Table.Body>
 				{rows.map((r) => {
 					const value = (query.data?.items || []).find(
-						(x) => x.service === r.service && x.metric === r.metric,
+						(x) =>
+							x.service === r.service &&
+							x.metric === r.metric &&
+							(x.kind ?? "threshold") === "threshold",
 					)?.threshold;
 
 					const defaultValue =
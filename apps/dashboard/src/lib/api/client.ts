@@ -5,14 +5,273 @@
  * OpenAPI 3.1.0 Specification for CostWatch API.
  * OpenAPI spec version: 2.0.0
  */
+export interface AckAlertIncidentRequest {
+	/** @minLength 1 */
+	by: string;
+}
+
+export type AlertDeliveriesResponseItemsItemDeliveredAt = string | null;
+
+export type AlertDeliveriesResponseItemsItemNextAttemptAt = string | null;
+
+export type AlertDeliveriesResponseItemsItemSeverity =
+	(typeof AlertDeliveriesResponseItemsItemSeverity)[keyof typeof AlertDeliveriesResponseItemsItemSeverity];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertDeliveriesResponseItemsItemSeverity = {
+	warning: "warning",
+	critical: "critical",
+} as const;
+
+export type AlertDeliveriesResponseItemsItemState =
+	(typeof AlertDeliveriesResponseItemsItemState)[keyof typeof AlertDeliveriesResponseItemsItemState];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertDeliveriesResponseItemsItemState = {
+	firing: "firing",
+	resolved: "resolved",
+} as const;
+
+export type AlertDeliveriesResponseItemsItemStatus =
+	(typeof AlertDeliveriesResponseItemsItemStatus)[keyof typeof AlertDeliveriesResponseItemsItemStatus];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertDeliveriesResponseItemsItemStatus = {
+	pending: "pending",
+	delivered: "delivered",
+	failed: "failed",
+} as const;
+
+export type AlertDeliveriesResponseItemsItem = {
+	attempts: number;
+	channel: string;
+	created_at: string;
+	delivered_at: AlertDeliveriesResponseItemsItemDeliveredAt;
+	id: number;
+	incident_id: number;
+	last_error?: string;
+	next_attempt_at: AlertDeliveriesResponseItemsItemNextAttemptAt;
+	severity: AlertDeliveriesResponseItemsItemSeverity;
+	state: AlertDeliveriesResponseItemsItemState;
+	status: AlertDeliveriesResponseItemsItemStatus;
+	summary: string;
+};
+
+export interface AlertDeliveriesResponse {
+	items: AlertDeliveriesResponseItemsItem[];
+}
+
+export type AlertIncidentResponseAckedAt = string | null;
+
+export type AlertIncidentResponseKind =
+	(typeof AlertIncidentResponseKind)[keyof typeof AlertIncidentResponseKind];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentResponseKind = {
+	threshold: "threshold",
+	budget: "budget",
+	anomaly: "anomaly",
+	baseline: "baseline",
+	change: "change",
+} as const;
+
+export type AlertIncidentResponseNotificationsItem = {
+	channel: string;
+	error?: string;
+	message: string;
+	sent_at: string;
+};
+
+export type AlertIncidentResponseResolvedAt = string | null;
+
+export type AlertIncidentResponseSeverity =
+	(typeof AlertIncidentResponseSeverity)[keyof typeof AlertIncidentResponseSeverity];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentResponseSeverity = {
+	warning: "warning",
+	critical: "critical",
+} as const;
+
+export type AlertIncidentResponseStatus =
+	(typeof AlertIncidentResponseStatus)[keyof typeof AlertIncidentResponseStatus];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentResponseStatus = {
+	open: "open",
+	resolved: "resolved",
+} as const;
+
+export interface AlertIncidentResponse {
+	acked_at: AlertIncidentResponseAckedAt;
+	acked_by?: string;
+	dimension?: string;
+	id: number;
+	kind: AlertIncidentResponseKind;
+	metric: string;
+	notifications?: AlertIncidentResponseNotificationsItem[];
+	overspend: number;
+	peak_cost: number;
+	period?: string;
+	resolved_at: AlertIncidentResponseResolvedAt;
+	service: string;
+	severity: AlertIncidentResponseSeverity;
+	started_at: string;
+	status: AlertIncidentResponseStatus;
+	summary: string;
+	updated_at: string;
+}
+
+export type AlertIncidentsResponseItemsItemAckedAt = string | null;
+
+export type AlertIncidentsResponseItemsItemKind =
+	(typeof AlertIncidentsResponseItemsItemKind)[keyof typeof AlertIncidentsResponseItemsItemKind];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentsResponseItemsItemKind = {
+	threshold: "threshold",
+	budget: "budget",
+	anomaly: "anomaly",
+	baseline: "baseline",
+	change: "change",
+} as const;
+
+export type AlertIncidentsResponseItemsItemResolvedAt = string | null;
+
+export type AlertIncidentsResponseItemsItemSeverity =
+	(typeof AlertIncidentsResponseItemsItemSeverity)[keyof typeof AlertIncidentsResponseItemsItemSeverity];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentsResponseItemsItemSeverity = {
+	warning: "warning",
+	critical: "critical",
+} as const;
+
+export type AlertIncidentsResponseItemsItemStatus =
+	(typeof AlertIncidentsResponseItemsItemStatus)[keyof typeof AlertIncidentsResponseItemsItemStatus];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertIncidentsResponseItemsItemStatus = {
+	open: "open",
+	resolved: "resolved",
+} as const;
+
+export type AlertIncidentsResponseItemsItem = {
+	acked_at: AlertIncidentsResponseItemsItemAckedAt;
+	acked_by?: string;
+	dimension?: string;
+	id: number;
+	kind: AlertIncidentsResponseItemsItemKind;
+	metric: string;
+	overspend: number;
+	peak_cost: number;
+	period?: string;
+	resolved_at: AlertIncidentsResponseItemsItemResolvedAt;
+	service: string;
+	severity: AlertIncidentsResponseItemsItemSeverity;
+	started_at: string;
+	status: AlertIncidentsResponseItemsItemStatus;
+	summary: string;
+	updated_at: string;
+};
+
+export interface AlertIncidentsResponse {
+	items: AlertIncidentsResponseItemsItem[];
+}
+
+export type AlertRuleBasis =
+	(typeof AlertRuleBasis)[keyof typeof AlertRuleBasis];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertRuleBasis = {
+	actual: "actual",
+	forecast: "forecast",
+} as const;
+
+export type AlertRuleKind = (typeof AlertRuleKind)[keyof typeof AlertRuleKind];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertRuleKind = {
+	threshold: "threshold",
+	budget: "budget",
+	anomaly: "anomaly",
+	baseline: "baseline",
+	change: "change",
+} as const;
+
+export type AlertRuleMethod =
+	(typeof AlertRuleMethod)[keyof typeof AlertRuleMethod];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertRuleMethod = {
+	zscore: "zscore",
+	mad: "mad",
+} as const;
+
+export type AlertRulePercentile =
+	(typeof AlertRulePercentile)[keyof typeof AlertRulePercentile];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertRulePercentile = {
+	p50: "p50",
+	p90: "p90",
+	p95: "p95",
+	max: "max",
+} as const;
+
+export type AlertRulePeriod =
+	(typeof AlertRulePeriod)[keyof typeof AlertRulePeriod];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertRulePeriod = {
+	daily: "daily",
+	weekly: "weekly",
+	monthly: "monthly",
+} as const;
+
 export interface AlertRule {
+	amount?: number;
+	baseline_days?: number;
+	baseline_weeks?: number;
+	basis?: AlertRuleBasis;
+	channels?: string[];
+	/** @minimum 0 */
+	critical?: number;
+	/** @minimum 60 */
+	escalate_after?: number;
+	kind?: AlertRuleKind;
+	method?: AlertRuleMethod;
 	metric: string;
+	percent?: number;
+	percentages?: number[];
+	percentile?: AlertRulePercentile;
+	period?: AlertRulePeriod;
+	recipients?: string[];
+	/** @minimum 60 */
+	renotify_interval?: number;
+	sensitivity?: number;
 	service: string;
-	threshold: number;
+	threshold?: number;
 }
 
 export type AlertRuleListResponseItemsItem = {
+	amount?: number;
+	baseline_days?: number;
+	baseline_weeks?: number;
+	basis?: string;
+	channels?: string[];
+	critical?: number;
+	escalate_after?: number;
+	kind?: string;
+	method?: string;
 	metric?: string;
+	percent?: number;
+	percentages?: number[];
+	percentile?: string;
+	period?: string;
+	recipients?: string[];
+	renotify_interval?: number;
+	sensitivity?: number;
 	service?: string;
 	threshold?: number;
 };
@@ -22,14 +281,44 @@ export interface AlertRuleListResponse {
 	readonly: boolean;
 }
 
+export type AlertWindowsQueryResponseItemsItemAckedAt = string | null;
+
 export type AlertWindowsQueryResponseItemsItemEnd = string | null;
 
+export type AlertWindowsQueryResponseItemsItemKind =
+	(typeof AlertWindowsQueryResponseItemsItemKind)[keyof typeof AlertWindowsQueryResponseItemsItemKind];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertWindowsQueryResponseItemsItemKind = {
+	threshold: "threshold",
+	anomaly: "anomaly",
+	baseline: "baseline",
+	change: "change",
+} as const;
+
+export type AlertWindowsQueryResponseItemsItemSeverity =
+	(typeof AlertWindowsQueryResponseItemsItemSeverity)[keyof typeof AlertWindowsQueryResponseItemsItemSeverity];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const AlertWindowsQueryResponseItemsItemSeverity = {
+	warning: "warning",
+	critical: "critical",
+} as const;
+
 export type AlertWindowsQueryResponseItemsItem = {
+	acked_at: AlertWindowsQueryResponseItemsItemAckedAt;
+	acked_by?: string;
+	baseline: string;
+	baseline_cost: number;
+	dimension?: string;
 	end: AlertWindowsQueryResponseItemsItemEnd;
 	expected_cost: number;
+	kind: AlertWindowsQueryResponseItemsItemKind;
 	metric: string;
 	real_cost: number;
 	service: string;
+	severity: AlertWindowsQueryResponseItemsItemSeverity;
+	silenced: boolean;
 	start: string;
 };
 
@@ -40,6 +329,184 @@ export interface AlertWindowsQueryResponse {
 	to_date: string;
 }
 
+export interface CreateSilenceRequest {
+	/** @minLength 1 */
+	author: string;
+	duration?: string;
+	ends_at?: string;
+	metric?: string;
+	/** @minLength 1 */
+	reason: string;
+	service?: string;
+	starts_at?: string;
+}
+
+export type ForecastResponseItemsItem = {
+	forecast: number;
+	lower: number;
+	metric: string;
+	month_to_date: number;
+	service: string;
+	upper: number;
+};
+
+export interface ForecastResponse {
+	from_date: string;
+	interval: number;
+	items: ForecastResponseItemsItem[];
+	to_date: string;
+}
+
+export type NotificationChannelConfig = { [key: string]: unknown };
+
+export type NotificationChannelType =
+	(typeof NotificationChannelType)[keyof typeof NotificationChannelType];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const NotificationChannelType = {
+	webhook: "webhook",
+	pagerduty: "pagerduty",
+	email: "email",
+} as const;
+
+export interface NotificationChannel {
+	config: NotificationChannelConfig;
+	created_at: string;
+	name: string;
+	type: NotificationChannelType;
+	updated_at: string;
+}
+
+export type NotificationChannelsResponseItemsItemConfig = {
+	[key: string]: unknown;
+};
+
+export type NotificationChannelsResponseItemsItemType =
+	(typeof NotificationChannelsResponseItemsItemType)[keyof typeof NotificationChannelsResponseItemsItemType];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const NotificationChannelsResponseItemsItemType = {
+	webhook: "webhook",
+	pagerduty: "pagerduty",
+	email: "email",
+} as const;
+
+export type NotificationChannelsResponseItemsItem = {
+	config: NotificationChannelsResponseItemsItemConfig;
+	created_at: string;
+	name: string;
+	type: NotificationChannelsResponseItemsItemType;
+	updated_at: string;
+};
+
+export interface NotificationChannelsResponse {
+	items: NotificationChannelsResponseItemsItem[];
+}
+
+export type PriceVersionsResponseItemsItemEffectiveFrom = string | null;
+
+export type PriceVersionsResponseItemsItemEffectiveTo = string | null;
+
+export type PriceVersionsResponseItemsItemTiersItemUpTo = number | null;
+
+export type PriceVersionsResponseItemsItemTiersItem = {
+	price: number;
+	up_to: PriceVersionsResponseItemsItemTiersItemUpTo;
+};
+
+export type PriceVersionsResponseItemsItem = {
+	currency: string;
+	effective_from: PriceVersionsResponseItemsItemEffectiveFrom;
+	effective_to: PriceVersionsResponseItemsItemEffectiveTo;
+	free_units: number;
+	metric: string;
+	price: number;
+	service: string;
+	tiers: PriceVersionsResponseItemsItemTiersItem[];
+	unit_size: number;
+};
+
+export interface PriceVersionsResponse {
+	items: PriceVersionsResponseItemsItem[];
+}
+
+export interface Silence {
+	author: string;
+	created_at: string;
+	ends_at: string;
+	id: number;
+	metric: string;
+	reason: string;
+	service: string;
+	starts_at: string;
+}
+
+export type SilencesResponseItemsItem = {
+	author: string;
+	created_at: string;
+	ends_at: string;
+	id: number;
+	metric: string;
+	reason: string;
+	service: string;
+	starts_at: string;
+};
+
+export interface SilencesResponse {
+	items: SilencesResponseItemsItem[];
+}
+
+export type TestNotificationRequestSeverity =
+	(typeof TestNotificationRequestSeverity)[keyof typeof TestNotificationRequestSeverity];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const TestNotificationRequestSeverity = {
+	warning: "warning",
+	critical: "critical",
+} as const;
+
+export type TestNotificationRequestState =
+	(typeof TestNotificationRequestState)[keyof typeof TestNotificationRequestState];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const TestNotificationRequestState = {
+	firing: "firing",
+	resolved: "resolved",
+} as const;
+
+export interface TestNotificationRequest {
+	channel?: string;
+	severity?: TestNotificationRequestSeverity;
+	state?: TestNotificationRequestState;
+}
+
+export interface TestNotificationResponse {
+	channel: string;
+	delivered: boolean;
+	error?: string;
+	response?: string;
+	status_code?: number;
+}
+
+export type UpdateNotificationChannelRequestConfig = { [key: string]: unknown };
+
+export type UpdateNotificationChannelRequestType =
+	(typeof UpdateNotificationChannelRequestType)[keyof typeof UpdateNotificationChannelRequestType];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const UpdateNotificationChannelRequestType = {
+	webhook: "webhook",
+	pagerduty: "pagerduty",
+	email: "email",
+} as const;
+
+export interface UpdateNotificationChannelRequest {
+	config: UpdateNotificationChannelRequestConfig;
+	/** @pattern ^[a-z0-9][a-z0-9_-]{0,62}$ */
+	name: string;
+	type: UpdateNotificationChannelRequestType;
+}
+
 export type UsagePercentilesResponseItemsItem = {
 	metric: string;
 	p50: number;
@@ -58,6 +525,7 @@ export interface UsagePercentilesResponse {
 
 export type UsageResponseItemsItem = {
 	cost: number;
+	dimension?: string;
 	metric: string;
 	service: string;
 	timestamp: string;
@@ -70,6 +538,231 @@ export interface UsageResponse {
 	to_date: string;
 }
 
+export type AlertDeliveriesParams = {
+	status?: string;
+	limit?: number;
+};
+
+export type AlertIncidentsParams = {
+	status?: string;
+	service?: string;
+	metric?: string;
+	from?: string;
+	to?: string;
+	limit?: number;
+};
+
+export type AlertWindowsParams = {
+	from?: string;
+	to?: string;
+	interval?: number;
+	service?: string;
+	metric?: string;
+	group_by?: string;
+	account?: string;
+	region?: string;
+};
+
+export type ForecastParams = {
+	service?: string;
+	metric?: string;
+	account?: string;
+	region?: string;
+};
+
+export type PriceVersionsParams = {
+	service?: string;
+	metric?: string;
+};
+
+export type SilencesParams = {
+	expired?: boolean;
+};
+
+export type UsageParams = {
+	from?: string;
+	to?: string;
+	interval?: number;
+	service?: string;
+	metric?: string;
+	group_by?: string;
+	account?: string;
+	region?: string;
+};
+
+export type UsagePercentilesParams = {
+	from?: string;
+	to?: string;
+	interval?: number;
+	service?: string;
+	metric?: string;
+	account?: string;
+	region?: string;
+};
+
+export type alertDeliveriesResponse200 = {
+	data: AlertDeliveriesResponse;
+	status: 200;
+};
+
+export type alertDeliveriesResponseComposite = alertDeliveriesResponse200;
+
+export type alertDeliveriesResponse = alertDeliveriesResponseComposite & {
+	headers: Headers;
+};
+
+export const getAlertDeliveriesUrl = (params?: AlertDeliveriesParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/alert-deliveries?${stringifiedParams}`
+		: "__API_URL__/alert-deliveries";
+};
+
+export const alertDeliveries = async (
+	params?: AlertDeliveriesParams,
+	options?: RequestInit,
+): Promise<alertDeliveriesResponse> => {
+	const res = await fetch(getAlertDeliveriesUrl(params), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: alertDeliveriesResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as alertDeliveriesResponse;
+};
+
+export type alertIncidentsResponse200 = {
+	data: AlertIncidentsResponse;
+	status: 200;
+};
+
+export type alertIncidentsResponseComposite = alertIncidentsResponse200;
+
+export type alertIncidentsResponse = alertIncidentsResponseComposite & {
+	headers: Headers;
+};
+
+export const getAlertIncidentsUrl = (params?: AlertIncidentsParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/alert-incidents?${stringifiedParams}`
+		: "__API_URL__/alert-incidents";
+};
+
+export const alertIncidents = async (
+	params?: AlertIncidentsParams,
+	options?: RequestInit,
+): Promise<alertIncidentsResponse> => {
+	const res = await fetch(getAlertIncidentsUrl(params), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: alertIncidentsResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as alertIncidentsResponse;
+};
+
+export type alertIncidentResponse200 = {
+	data: AlertIncidentResponse;
+	status: 200;
+};
+
+export type alertIncidentResponseComposite = alertIncidentResponse200;
+
+export type alertIncidentResponse = alertIncidentResponseComposite & {
+	headers: Headers;
+};
+
+export const getAlertIncidentUrl = (id: string) => {
+	return `__API_URL__/alert-incidents/${id}`;
+};
+
+export const alertIncident = async (
+	id: string,
+	options?: RequestInit,
+): Promise<alertIncidentResponse> => {
+	const res = await fetch(getAlertIncidentUrl(id), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: alertIncidentResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as alertIncidentResponse;
+};
+
+export type ackAlertIncidentResponse200 = {
+	data: AlertIncidentResponse;
+	status: 200;
+};
+
+export type ackAlertIncidentResponseComposite = ackAlertIncidentResponse200;
+
+export type ackAlertIncidentResponse = ackAlertIncidentResponseComposite & {
+	headers: Headers;
+};
+
+export const getAckAlertIncidentUrl = (id: string) => {
+	return `__API_URL__/alert-incidents/${id}/ack`;
+};
+
+export const ackAlertIncident = async (
+	id: string,
+	ackAlertIncidentRequest: AckAlertIncidentRequest,
+	options?: RequestInit,
+): Promise<ackAlertIncidentResponse> => {
+	const res = await fetch(getAckAlertIncidentUrl(id), {
+		...options,
+		method: "POST",
+		headers: { "Content-Type": "application/json", ...options?.headers },
+		body: JSON.stringify(ackAlertIncidentRequest),
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: ackAlertIncidentResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as ackAlertIncidentResponse;
+};
+
 export type alertRulesResponse200 = {
 	data: AlertRuleListResponse;
 	status: 200;
@@ -150,14 +843,27 @@ export type alertWindowsResponse = alertWindowsResponseComposite & {
 	headers: Headers;
 };
 
-export const getAlertWindowsUrl = () => {
-	return "__API_URL__/alert-windows";
+export const getAlertWindowsUrl = (params?: AlertWindowsParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/alert-windows?${stringifiedParams}`
+		: "__API_URL__/alert-windows";
 };
 
 export const alertWindows = async (
+	params?: AlertWindowsParams,
 	options?: RequestInit,
 ): Promise<alertWindowsResponse> => {
-	const res = await fetch(getAlertWindowsUrl(), {
+	const res = await fetch(getAlertWindowsUrl(params), {
 		...options,
 		method: "GET",
 	});
@@ -172,6 +878,354 @@ export const alertWindows = async (
 	} as alertWindowsResponse;
 };
 
+export type forecastResponse200 = {
+	data: ForecastResponse;
+	status: 200;
+};
+
+export type forecastResponseComposite = forecastResponse200;
+
+export type forecastResponse = forecastResponseComposite & {
+	headers: Headers;
+};
+
+export const getForecastUrl = (params?: ForecastParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/forecast?${stringifiedParams}`
+		: "__API_URL__/forecast";
+};
+
+export const forecast = async (
+	params?: ForecastParams,
+	options?: RequestInit,
+): Promise<forecastResponse> => {
+	const res = await fetch(getForecastUrl(params), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: forecastResponse["data"] = body ? JSON.parse(body) : {};
+
+	return { data, status: res.status, headers: res.headers } as forecastResponse;
+};
+
+export type notificationChannelsResponse200 = {
+	data: NotificationChannelsResponse;
+	status: 200;
+};
+
+export type notificationChannelsResponseComposite =
+	notificationChannelsResponse200;
+
+export type notificationChannelsResponse =
+	notificationChannelsResponseComposite & {
+		headers: Headers;
+	};
+
+export const getNotificationChannelsUrl = () => {
+	return "__API_URL__/notification-channels";
+};
+
+export const notificationChannels = async (
+	options?: RequestInit,
+): Promise<notificationChannelsResponse> => {
+	const res = await fetch(getNotificationChannelsUrl(), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: notificationChannelsResponse["data"] =
+		body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as notificationChannelsResponse;
+};
+
+export type updateNotificationChannelResponse200 = {
+	data: NotificationChannel;
+	status: 200;
+};
+
+export type updateNotificationChannelResponseComposite =
+	updateNotificationChannelResponse200;
+
+export type updateNotificationChannelResponse =
+	updateNotificationChannelResponseComposite & {
+		headers: Headers;
+	};
+
+export const getUpdateNotificationChannelUrl = () => {
+	return "__API_URL__/notification-channels";
+};
+
+export const updateNotificationChannel = async (
+	updateNotificationChannelRequest: UpdateNotificationChannelRequest,
+	options?: RequestInit,
+): Promise<updateNotificationChannelResponse> => {
+	const res = await fetch(getUpdateNotificationChannelUrl(), {
+		...options,
+		method: "PUT",
+		headers: { "Content-Type": "application/json", ...options?.headers },
+		body: JSON.stringify(updateNotificationChannelRequest),
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: updateNotificationChannelResponse["data"] =
+		body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as updateNotificationChannelResponse;
+};
+
+export type testNotificationResponse200 = {
+	data: TestNotificationResponse;
+	status: 200;
+};
+
+export type testNotificationResponseComposite = testNotificationResponse200;
+
+export type testNotificationResponse = testNotificationResponseComposite & {
+	headers: Headers;
+};
+
+export const getTestNotificationUrl = () => {
+	return "__API_URL__/notification-channels/test";
+};
+
+export const testNotification = async (
+	testNotificationRequest: TestNotificationRequest,
+	options?: RequestInit,
+): Promise<testNotificationResponse> => {
+	const res = await fetch(getTestNotificationUrl(), {
+		...options,
+		method: "POST",
+		headers: { "Content-Type": "application/json", ...options?.headers },
+		body: JSON.stringify(testNotificationRequest),
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: testNotificationResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as testNotificationResponse;
+};
+
+export type deleteNotificationChannelResponse200 = {
+	data: NotificationChannel;
+	status: 200;
+};
+
+export type deleteNotificationChannelResponseComposite =
+	deleteNotificationChannelResponse200;
+
+export type deleteNotificationChannelResponse =
+	deleteNotificationChannelResponseComposite & {
+		headers: Headers;
+	};
+
+export const getDeleteNotificationChannelUrl = (name: string) => {
+	return `__API_URL__/notification-channels/${name}`;
+};
+
+export const deleteNotificationChannel = async (
+	name: string,
+	options?: RequestInit,
+): Promise<deleteNotificationChannelResponse> => {
+	const res = await fetch(getDeleteNotificationChannelUrl(name), {
+		...options,
+		method: "DELETE",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: deleteNotificationChannelResponse["data"] =
+		body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as deleteNotificationChannelResponse;
+};
+
+export type priceVersionsResponse200 = {
+	data: PriceVersionsResponse;
+	status: 200;
+};
+
+export type priceVersionsResponseComposite = priceVersionsResponse200;
+
+export type priceVersionsResponse = priceVersionsResponseComposite & {
+	headers: Headers;
+};
+
+export const getPriceVersionsUrl = (params?: PriceVersionsParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/price-versions?${stringifiedParams}`
+		: "__API_URL__/price-versions";
+};
+
+export const priceVersions = async (
+	params?: PriceVersionsParams,
+	options?: RequestInit,
+): Promise<priceVersionsResponse> => {
+	const res = await fetch(getPriceVersionsUrl(params), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: priceVersionsResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as priceVersionsResponse;
+};
+
+export type silencesResponse200 = {
+	data: SilencesResponse;
+	status: 200;
+};
+
+export type silencesResponseComposite = silencesResponse200;
+
+export type silencesResponse = silencesResponseComposite & {
+	headers: Headers;
+};
+
+export const getSilencesUrl = (params?: SilencesParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/silences?${stringifiedParams}`
+		: "__API_URL__/silences";
+};
+
+export const silences = async (
+	params?: SilencesParams,
+	options?: RequestInit,
+): Promise<silencesResponse> => {
+	const res = await fetch(getSilencesUrl(params), {
+		...options,
+		method: "GET",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: silencesResponse["data"] = body ? JSON.parse(body) : {};
+
+	return { data, status: res.status, headers: res.headers } as silencesResponse;
+};
+
+export type createSilenceResponse201 = {
+	data: Silence;
+	status: 201;
+};
+
+export type createSilenceResponseComposite = createSilenceResponse201;
+
+export type createSilenceResponse = createSilenceResponseComposite & {
+	headers: Headers;
+};
+
+export const getCreateSilenceUrl = () => {
+	return "__API_URL__/silences";
+};
+
+export const createSilence = async (
+	createSilenceRequest: CreateSilenceRequest,
+	options?: RequestInit,
+): Promise<createSilenceResponse> => {
+	const res = await fetch(getCreateSilenceUrl(), {
+		...options,
+		method: "POST",
+		headers: { "Content-Type": "application/json", ...options?.headers },
+		body: JSON.stringify(createSilenceRequest),
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: createSilenceResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as createSilenceResponse;
+};
+
+export type expireSilenceResponse200 = {
+	data: Silence;
+	status: 200;
+};
+
+export type expireSilenceResponseComposite = expireSilenceResponse200;
+
+export type expireSilenceResponse = expireSilenceResponseComposite & {
+	headers: Headers;
+};
+
+export const getExpireSilenceUrl = (id: string) => {
+	return `__API_URL__/silences/${id}/expire`;
+};
+
+export const expireSilence = async (
+	id: string,
+	options?: RequestInit,
+): Promise<expireSilenceResponse> => {
+	const res = await fetch(getExpireSilenceUrl(id), {
+		...options,
+		method: "POST",
+	});
+
+	const body = [204, 205, 304].includes(res.status) ? null : await res.text();
+	const data: expireSilenceResponse["data"] = body ? JSON.parse(body) : {};
+
+	return {
+		data,
+		status: res.status,
+		headers: res.headers,
+	} as expireSilenceResponse;
+};
+
 export type usageResponse200 = {
 	data: UsageResponse;
 	status: 200;
@@ -183,12 +1237,27 @@ export type usageResponse = usageResponseComposite & {
 	headers: Headers;
 };
 
-export const getUsageUrl = () => {
-	return "__API_URL__/usage";
+export const getUsageUrl = (params?: UsageParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/usage?${stringifiedParams}`
+		: "__API_URL__/usage";
 };
 
-export const usage = async (options?: RequestInit): Promise<usageResponse> => {
-	const res = await fetch(getUsageUrl(), {
+export const usage = async (
+	params?: UsageParams,
+	options?: RequestInit,
+): Promise<usageResponse> => {
+	const res = await fetch(getUsageUrl(params), {
 		...options,
 		method: "GET",
 	});
@@ -210,14 +1279,27 @@ export type usagePercentilesResponse = usagePercentilesResponseComposite & {
 	headers: Headers;
 };
 
-export const getUsagePercentilesUrl = () => {
-	return "__API_URL__/usage-percentiles";
+export const getUsagePercentilesUrl = (params?: UsagePercentilesParams) => {
+	const normalizedParams = new URLSearchParams();
+
+	Object.entries(params || {}).forEach(([key, value]) => {
+		if (value !== undefined) {
+			normalizedParams.append(key, value === null ? "null" : value.toString());
+		}
+	});
+
+	const stringifiedParams = normalizedParams.toString();
+
+	return stringifiedParams.length > 0
+		? `__API_URL__/usage-percentiles?${stringifiedParams}`
+		: "__API_URL__/usage-percentiles";
 };
 
 export const usagePercentiles = async (
+	params?: UsagePercentilesParams,
 	options?: RequestInit,
 ): Promise<usagePercentilesResponse> => {
-	const res = await fetch(getUsagePercentilesUrl(), {
+	const res = await fetch(getUsagePercentilesUrl(params), {
 		...options,
 		method: "GET",
 	});
@@ -15,6 +15,7 @@ import (
 	"github.com/tailbits/costwatch/internal/appconfig"
 	"github.com/tailbits/costwatch/internal/clickstore"
 	"github.com/tailbits/costwatch/internal/costwatch"
+	"github.com/tailbits/costwatch/internal/costwatch/infra/catalog"
 	"github.com/tailbits/costwatch/internal/health"
 	"github.com/tailbits/costwatch/internal/monolith"
 	"github.com/tailbits/costwatch/internal/provider/aws/cloudwatch"
@@ -78,7 +79,12 @@ func run(log *slog.Logger, env string) error {
 
 	// ===========================================================================
 	// CostWatch
-	cw, err := costwatch.New(ctx, log, cs)
+	ctlg, err := catalog.NewFromEnv()
+	if err != nil {
+		return fmt.Errorf("catalog.NewFromEnv: %w", err)
+	}
+
+	cw, err := costwatch.New(ctx, log, cs, ctlg)
 	if err != nil {
 		return fmt.Errorf("costwatch.New: %w", err)
 	}
@@ -92,12 +98,37 @@ func run(log *slog.Logger, env string) error {
 		return fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
-	svc := cloudwatch.NewService(awsCfg)
+	// Collect from every configured region and account (via STS AssumeRole).
+	targets, err := cloudwatch.NewTargets(ctx, log.WithGroup("aws"), awsCfg,
+		cloudwatch.SplitList(os.Getenv("AWS_REGIONS")),
+		cloudwatch.SplitList(os.Getenv("AWS_ACCOUNT_ROLE_ARNS")),
+	)
+	if err != nil {
+		return fmt.Errorf("cloudwatch.NewTargets: %w", err)
+	}
+
+	svc := cloudwatch.NewService(awsCfg, targets...)
 	costwatch.RegisterService(svc)
 
-	ib := metric.NewIncomingBytes(log.WithGroup("incoming_bytes"), awscloudwatch.NewFromConfig(awsCfg))
+	// Optionally break IncomingBytes down per CloudWatch dimension (e.g. LogGroupName).
+	ibDims := cloudwatch.SplitList(os.Getenv("INCOMING_BYTES_DIMENSIONS"))
+	cwClient := awscloudwatch.NewFromConfig(awsCfg)
+	ib := metric.NewIncomingBytes(log.WithGroup("incoming_bytes"), cwClient, ibDims...)
 	svc.NewMetric(ib)
 
+	// Generic CloudWatch metrics defined in CLOUDWATCH_METRICS_PATH.
+	mCfgs, err := metric.ConfigsFromEnv()
+	if err != nil {
+		return fmt.Errorf("metric.ConfigsFromEnv: %w", err)
+	}
+	for _, mc := range mCfgs {
+		gm, err := metric.NewGeneric(log.WithGroup("cloudwatch_metric"), cwClient, mc)
+		if err != nil {
+			return fmt.Errorf("metric.NewGeneric: %w", err)
+		}
+		svc.NewMetric(gm)
+	}
+
 	// CoinGecko provider with BTC/USD metrics (disabled when DEMO=false)
 	if cg.Enabled() {
 		cgSvc := cg.NewService()
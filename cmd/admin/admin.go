@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
@@ -9,6 +12,7 @@ import (
 
 	"github.com/tailbits/costwatch/internal/clickstore"
 	cwapi "github.com/tailbits/costwatch/internal/costwatch/api"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
 	"github.com/tailbits/costwatch/internal/monolith"
 	"github.com/tailbits/costwatch/internal/spec"
 )
@@ -36,6 +40,11 @@ func main() {
 			log.Error("Failed to generate OpenAPI spec", "error", err.Error())
 			os.Exit(1)
 		}
+	case "test-notification":
+		if err := testNotification(context.Background(), log, os.Args[2:]); err != nil {
+			log.Error("Failed to send test notification", "error", err.Error())
+			os.Exit(1)
+		}
 	default:
 		usage()
 		os.Exit(2)
@@ -48,6 +57,8 @@ func usage() {
 	fmt.Fprintln(os.Stderr, "Available commands:")
 	fmt.Fprintln(os.Stderr, "  seed-clickhouse\tInitialize ClickHouse schema")
 	fmt.Fprintln(os.Stderr, "  openapi\t\tPrint OpenAPI 3.1 spec to stdout")
+	fmt.Fprintln(os.Stderr, "  test-notification [-severity warning|critical] [-state firing|resolved] [channel]")
+	fmt.Fprintln(os.Stderr, "\t\t\tSend a sample alert to a notification channel and print the result")
 	fmt.Fprintln(os.Stderr, "")
 }
 
@@ -125,3 +136,35 @@ func printOpenAPISpec(ctx context.Context, log *slog.Logger) error {
 	os.Stdout.Write([]byte("\n"))
 	return nil
 }
+
+// errNotDelivered makes test-notification exit with an error when the sample
+// alert was not delivered.
+var errNotDelivered = errors.New("notification not delivered")
+
+func testNotification(ctx context.Context, log *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("test-notification", flag.ExitOnError)
+	severity := fs.String("severity", "warning", "severity of the sample alert: warning or critical")
+	state := fs.String("state", "firing", "state of the sample alert: firing or resolved")
+	_ = fs.Parse(args)
+
+	// Notifiers do not read metrics, so the API does not need ClickHouse.
+	client := &clickstore.Client{Conn: clickstore.NewTestStore(log)}
+	cw, err := cwapi.New(ctx, log, client)
+	if err != nil {
+		return fmt.Errorf("api.New: %w", err)
+	}
+	res, err := cw.SendTestNotification(ctx, fs.Arg(0), port.Severity(*severity), port.AlertState(*state))
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		return err
+	}
+	if !res.Delivered {
+		return errNotDelivered
+	}
+	return nil
+}
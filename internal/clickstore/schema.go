@@ -2,13 +2,29 @@ package clickstore
 
 import (
 	"context"
-	_ "embed"
+	"embed"
 	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
 )
 
 //go:embed sql/schema.sql
 var schemaSQL string
 
+//go:embed sql/schema_migrations.sql
+var schemaMigrationsSQL string
+
+// migrations upgrade tables created by earlier versions of the schema. Each
+// file holds a single statement with a %s placeholder for the metrics table.
+// They are applied in lexical order, each at most once: applied migrations
+// are recorded in the schema_migrations table. Only the last one changes the
+// sorting key, since ClickHouse only lets an ALTER append the columns it adds.
+//
+//go:embed sql/migrations/*.sql
+var migrationsFS embed.FS
+
 func (c *Client) Setup(ctx context.Context, dbName string) error {
 	if dbName == "" {
 		return fmt.Errorf("setup: empty database name")
@@ -16,14 +32,77 @@ func (c *Client) Setup(ctx context.Context, dbName string) error {
 
 	quotedDB := fmt.Sprintf("`%s`", dbName)
 	tableFQN := fmt.Sprintf("%s.`metrics`", quotedDB)
+	migrationsFQN := fmt.Sprintf("%s.`schema_migrations`", quotedDB)
 
 	if err := c.Exec(ctx, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", quotedDB)); err != nil {
 		return fmt.Errorf("setup.CreateDB: %w", err)
 	}
 
+	var existed uint64
+	if err := c.QueryRow(ctx, "select count() from system.tables where database = ? and name = 'metrics'", dbName).Scan(&existed); err != nil {
+		return fmt.Errorf("setup.TableExists: %w", err)
+	}
+
+	if err := c.Exec(ctx, fmt.Sprintf(schemaMigrationsSQL, migrationsFQN)); err != nil {
+		return fmt.Errorf("setup.CreateMigrationsTable: %w", err)
+	}
+
 	if err := c.Exec(ctx, fmt.Sprintf(schemaSQL, tableFQN)); err != nil {
 		return fmt.Errorf("setup.CreateTable: %w", err)
 	}
 
+	// A table created from schema.sql is already up to date: its migrations
+	// are only recorded.
+	if err := c.migrate(ctx, tableFQN, migrationsFQN, existed == 0); err != nil {
+		return fmt.Errorf("setup.Migrate: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) migrate(ctx context.Context, tableFQN, migrationsFQN string, recordOnly bool) error {
+	names, err := fs.Glob(migrationsFS, "sql/migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	rows, err := c.Query(ctx, fmt.Sprintf("select name from %s", migrationsFQN))
+	if err != nil {
+		return fmt.Errorf("read applied migrations: %w", err)
+	}
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("read applied migrations: %w", err)
+		}
+		applied[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read applied migrations: %w", err)
+	}
+
+	for _, name := range names {
+		base := path.Base(name)
+		if applied[base] {
+			continue
+		}
+		if !recordOnly {
+			stmt, err := migrationsFS.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", name, err)
+			}
+			if err := c.Exec(ctx, fmt.Sprintf(string(stmt), tableFQN)); err != nil {
+				return fmt.Errorf("exec %s: %w", name, err)
+			}
+		}
+		if err := c.Exec(ctx, fmt.Sprintf("insert into %s (name, applied_at) values (?, ?)", migrationsFQN), base, time.Now().UTC()); err != nil {
+			return fmt.Errorf("record %s: %w", name, err)
+		}
+	}
+
 	return nil
 }
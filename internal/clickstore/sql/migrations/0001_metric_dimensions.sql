@@ -0,0 +1,2 @@
+alter table %s
+  add column if not exists dimensions Map(String, String)
@@ -0,0 +1,12 @@
+alter table %s
+  add column if not exists dimensions_key String,
+  add column if not exists account LowCardinality(String),
+  add column if not exists region LowCardinality(String),
+  modify order by (
+    service,
+    metric,
+    timestamp,
+    dimensions_key,
+    account,
+    region
+  )
@@ -2,14 +2,21 @@ create table if not exists %s (
   service String,
   metric String,
   value Float64,
-  timestamp DateTime64(3, 'UTC')
+  timestamp DateTime64(3, 'UTC'),
+  dimensions Map(String, String),
+  dimensions_key String,
+  account LowCardinality(String),
+  region LowCardinality(String)
 )
 ENGINE = ReplacingMergeTree()
 TTL toDateTime(timestamp) + toIntervalDay(90)
 order by (
   service,
   metric,
-  timestamp
+  timestamp,
+  dimensions_key,
+  account,
+  region
 )
 primary key (
   service,
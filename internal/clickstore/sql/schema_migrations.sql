@@ -0,0 +1,6 @@
+create table if not exists %s (
+  name String,
+  applied_at DateTime64(3, 'UTC')
+)
+ENGINE = MergeTree()
+order by name
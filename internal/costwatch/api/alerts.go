@@ -1,6 +1,7 @@
 package api
 
 import (
+	"cmp"
 	"context"
 	_ "embed"
 	"encoding/json"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/app"
 	"github.com/tailbits/costwatch/internal/costwatch/port"
 )
 
@@ -41,12 +43,102 @@ type UpdateAlertThresholdRequest struct {
 	Threshold float64 `json:"threshold"`
 }
 
-// UpdateAlertRule upserts a threshold keyed by service+metric.
+// UpdateAlertRule upserts a rule keyed by service+metric+kind+period. Fields
+// left out of the payload keep their stored value, so that a partial update,
+// such as the dashboard's threshold edit, does not clear the others.
 func (a *API) UpdateAlertRule(ctx context.Context, _ *http.Request, ent *AlertRule, _ model.Nil) (res *AlertRule, err error) {
-	if err := a.alert.Alerts.UpsertRule(ctx, port.AlertRule{Service: ent.Service, Metric: ent.Metric, Threshold: ent.Threshold}); err != nil {
+	rule := ent.toPort()
+	rules, err := a.alert.Alerts.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rules.List: %w", err)
+	}
+	for _, prev := range rules {
+		if prev.Service != rule.Service || prev.Metric != rule.Metric ||
+			cmp.Or(prev.Kind, port.RuleThreshold) != cmp.Or(rule.Kind, port.RuleThreshold) || prev.Period != rule.Period {
+			continue
+		}
+		merged, err := ent.mergeOnto(newAlertRule(prev))
+		if err != nil {
+			return nil, err
+		}
+		rule = merged.toPort()
+		break
+	}
+	if err := app.ValidateRule(&rule); err != nil {
+		return nil, model.ValidationError{Errors: []model.FieldError{{Message: err.Error()}}}
+	}
+	if err := a.checkChannels(ctx, rule.Channels); err != nil {
+		return nil, err
+	}
+	if err := a.alert.Alerts.UpsertRule(ctx, rule); err != nil {
 		return nil, fmt.Errorf("rules.Upsert: %w", err)
 	}
-	return ent, nil
+	res = newAlertRule(rule)
+	return res, nil
+}
+
+func (r *AlertRule) toPort() port.AlertRule {
+	return port.AlertRule{
+		Service:     r.Service,
+		Metric:      r.Metric,
+		Kind:        port.RuleKind(r.Kind),
+		Threshold:   r.Threshold,
+		Period:      port.BudgetPeriod(r.Period),
+		Amount:      r.Amount,
+		Percentages: r.Percentages,
+		Basis:       port.BudgetBasis(r.Basis),
+
+		Method:        port.AnomalyMethod(r.Method),
+		Sensitivity:   r.Sensitivity,
+		BaselineWeeks: r.BaselineWeeks,
+
+		Percent:      r.Percent,
+		Percentile:   port.Percentile(r.Percentile),
+		BaselineDays: r.BaselineDays,
+
+		RenotifyInterval: time.Duration(r.RenotifyInterval) * time.Second,
+		Critical:         r.Critical,
+		EscalateAfter:    time.Duration(r.EscalateAfter) * time.Second,
+
+		Recipients: r.Recipients,
+		Channels:   r.Channels,
+	}
+}
+
+func newAlertRule(r port.AlertRule) *AlertRule {
+	kind := r.Kind
+	if kind == "" {
+		kind = port.RuleThreshold
+	}
+	renotify := r.RenotifyInterval
+	if renotify == 0 {
+		renotify = port.DefaultRenotifyInterval
+	}
+	return &AlertRule{
+		Service:     r.Service,
+		Metric:      r.Metric,
+		Kind:        string(kind),
+		Threshold:   r.Threshold,
+		Period:      string(r.Period),
+		Amount:      r.Amount,
+		Percentages: r.Percentages,
+		Basis:       string(r.Basis),
+
+		Method:        string(r.Method),
+		Sensitivity:   r.Sensitivity,
+		BaselineWeeks: r.BaselineWeeks,
+
+		Percent:      r.Percent,
+		Percentile:   string(r.Percentile),
+		BaselineDays: r.BaselineDays,
+
+		RenotifyInterval: int(renotify / time.Second),
+		Critical:         r.Critical,
+		EscalateAfter:    int(r.EscalateAfter / time.Second),
+
+		Recipients: r.Recipients,
+		Channels:   r.Channels,
+	}
 }
 
 func (a *API) AlertRules(ctx context.Context, _ *http.Request, _ model.Nil) (res *AlertRuleListResponse, err error) {
@@ -56,7 +148,7 @@ func (a *API) AlertRules(ctx context.Context, _ *http.Request, _ model.Nil) (res
 	}
 	items := make([]AlertRule, 0, len(recs))
 	for _, rec := range recs {
-		items = append(items, AlertRule{Service: rec.Service, Metric: rec.Metric, Threshold: rec.Threshold})
+		items = append(items, *newAlertRule(rec))
 	}
 
 	_, readonly := os.LookupEnv("ALERT_RULES")
@@ -64,8 +156,8 @@ func (a *API) AlertRules(ctx context.Context, _ *http.Request, _ model.Nil) (res
 	return res, nil
 }
 
-func (a *API) computeAlertWindows(ctx context.Context, start, end time.Time, interval int) ([]AlertWindow, error) {
-	wins, err := a.alert.ComputeWindows(ctx, start, end, time.Duration(interval)*time.Second)
+func (a *API) computeAlertWindows(ctx context.Context, start, end time.Time, interval int, q port.MetricsQuery) ([]AlertWindow, error) {
+	wins, err := a.alert.ComputeWindows(ctx, start, end, time.Duration(interval)*time.Second, q)
 	if err != nil {
 		return nil, err
 	}
@@ -85,10 +177,18 @@ func (a *API) computeAlertWindows(ctx context.Context, start, end time.Time, int
 		res = append(res, AlertWindow{
 			Service:      w.Service,
 			Metric:       w.Metric,
+			Dimension:    w.Dimension,
+			Kind:         string(w.Kind),
+			Baseline:     w.Baseline,
 			Start:        w.Start,
 			End:          endPtr,
-			ExpectedCost: w.Threshold * float64(w.Hours),
+			BaselineCost: w.BaselineCost,
+			ExpectedCost: w.Expected,
 			RealCost:     w.RealCost,
+			Severity:     string(w.Severity),
+			Silenced:     w.Silenced,
+			AckedAt:      timePtr(w.AckedAt),
+			AckedBy:      w.AckedBy,
 		})
 	}
 	return res, nil
@@ -118,13 +218,39 @@ func (r *AlertWindowsQueryResponse) Unmarshal(data json.RawMessage) error {
 	return json.Unmarshal(data, r)
 }
 
+// AlertWindowsParams are the query parameters accepted by the alert windows endpoint.
+type AlertWindowsParams struct {
+	// From and To bound the range as RFC 3339 timestamps (default: the last 7 days).
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Interval is the bucket size in seconds (default: 3600).
+	Interval int `json:"interval"`
+	// Service and Metric restrict windows to a single service/metric.
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+	// GroupBy evaluates thresholds per value of the given metric dimension, or
+	// per AWS "account" or "region".
+	GroupBy string `json:"group_by"`
+	// Account and Region restrict windows to a single AWS account or region.
+	Account string `json:"account"`
+	Region  string `json:"region"`
+}
+
 // AlertWindows returns contiguous windows where hourly cost exceeded thresholds.
-func (a *API) AlertWindows(ctx context.Context, _ *http.Request, _ model.Nil) (res *AlertWindowsQueryResponse, err error) {
-	end := time.Now().UTC()
-	start := end.Add(-7 * 24 * time.Hour)
-	interval := 3600
+func (a *API) AlertWindows(ctx context.Context, _ *http.Request, params AlertWindowsParams) (res *AlertWindowsQueryResponse, err error) {
+	start, end, interval, err := queryRange(params.From, params.To, params.Interval)
+	if err != nil {
+		return nil, err
+	}
 
-	windows, err := a.computeAlertWindows(ctx, start, end, interval)
+	q := port.MetricsQuery{
+		GroupBy: params.GroupBy,
+		Service: params.Service,
+		Metric:  params.Metric,
+		Account: params.Account,
+		Region:  params.Region,
+	}
+	windows, err := a.computeAlertWindows(ctx, start, end, interval, q)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/magicbell/mason"
@@ -12,41 +14,78 @@ import (
 	chinfra "github.com/tailbits/costwatch/internal/costwatch/infra/clickhouse"
 	envinfra "github.com/tailbits/costwatch/internal/costwatch/infra/env"
 	sqlinfra "github.com/tailbits/costwatch/internal/costwatch/infra/sqlite"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
 	"github.com/tailbits/costwatch/internal/sqlstore"
 )
 
 // API wires ClickHouse and CostWatch and exposes HTTP routes.
 type API struct {
-	log   *slog.Logger
-	alert *app.AlertService
-	usage *app.UsageService
+	log       *slog.Logger
+	alert     *app.AlertService
+	usage     *app.UsageService
+	forecast  *app.ForecastService
+	incidents port.IncidentsRepo // nil when SQLite is unavailable
+	silences  port.SilencesRepo  // nil when SQLite is unavailable
+	outbox    port.OutboxRepo    // nil when SQLite is unavailable
+	channels  port.ChannelsRepo  // nil when SQLite is unavailable
 }
 
 // New constructs the API with a pre-initialized ClickHouse client.
 func New(_ context.Context, log *slog.Logger, store *clickstore.Client) (*API, error) {
 	repo := chinfra.NewMetricsRepo(store)
-	ctlg := ctlinfra.GlobalRegistryCatalog{}
+	ctlg, err := ctlinfra.NewFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("catalog.NewFromEnv: %w", err)
+	}
+
+	// Incidents and silences are kept in SQLite even when alert rules come
+	// from the env.
+	alertsDB, dbErr := sqlstore.Open()
+	var (
+		incidents port.IncidentsRepo
+		silences  port.SilencesRepo
+		outbox    port.OutboxRepo
+		channels  port.ChannelsRepo
+	)
+	if dbErr != nil {
+		log.Warn("sqlite unavailable, alert incidents and silences are disabled", "error", dbErr)
+	} else {
+		incidents = sqlinfra.NewIncidentsRepo(alertsDB)
+		silences = sqlinfra.NewSilencesRepo(alertsDB)
+		outbox = sqlinfra.NewOutboxRepo(alertsDB)
+		channels = sqlinfra.NewChannelsRepo(alertsDB)
+	}
 
 	var a app.AlertService
 	if os.Getenv("ALERT_RULES") != "" {
-		a = *app.NewAlertService(repo, envinfra.NewAlertsRepos(), nilNotifier{}, ctlg)
+		var state port.AlertsRepo
+		if dbErr == nil {
+			state = sqlinfra.NewAlertsRepos(alertsDB)
+		}
+		a = *app.NewAlertService(repo, envinfra.NewAlertsRepos(state), nilNotifier{}, ctlg)
 	} else {
-		alertsDB, _ := sqlstore.Open()
 		sqlRepo := sqlinfra.NewAlertsRepos(alertsDB)
 		a = *app.NewAlertService(repo, sqlRepo, nilNotifier{}, ctlg)
 	}
+	a.Incidents = incidents
+	a.Silences = silences
 	usage := app.NewUsageService(repo, ctlg)
 
 	return &API{
-		log:   log,
-		alert: &a,
-		usage: usage,
+		log:       log,
+		alert:     &a,
+		usage:     usage,
+		forecast:  app.NewForecastService(usage),
+		incidents: incidents,
+		silences:  silences,
+		outbox:    outbox,
+		channels:  channels,
 	}, nil
 }
 
 type nilNotifier struct{}
 
-func (nilNotifier) Send(ctx context.Context, text string) error { return nil }
+func (nilNotifier) Send(ctx context.Context, ev port.AlertEvent) error { return nil }
 
 // SetupRoutes registers HTTP routes on the provided Mason API.
 func (a *API) SetupRoutes(api *mason.API) {
@@ -59,6 +98,14 @@ func (a *API) SetupRoutes(api *mason.API) {
 		Path("/usage-percentiles").
 		WithOpID("usage_percentiles"))
 
+	grp.Register(mason.HandleGet(a.Forecast).
+		Path("/forecast").
+		WithOpID("forecast"))
+
+	grp.Register(mason.HandleGet(a.PriceVersions).
+		Path("/price-versions").
+		WithOpID("price_versions"))
+
 	grp.Register(mason.HandleGet(a.AlertRules).
 		Path("/alert-rules").
 		WithOpID("alert_rules"))
@@ -70,4 +117,52 @@ func (a *API) SetupRoutes(api *mason.API) {
 	grp.Register(mason.HandleGet(a.AlertWindows).
 		Path("/alert-windows").
 		WithOpID("alert_windows"))
+
+	grp.Register(mason.HandleGet(a.AlertIncidents).
+		Path("/alert-incidents").
+		WithOpID("alert_incidents"))
+
+	grp.Register(mason.HandleGet(a.AlertIncident).
+		Path("/alert-incidents/{id}").
+		WithOpID("alert_incident"))
+
+	grp.Register(mason.HandlePost(a.AckAlertIncident).
+		Path("/alert-incidents/{id}/ack").
+		WithOpID("ack_alert_incident").
+		WithSuccessCode(http.StatusOK))
+
+	grp.Register(mason.HandleGet(a.AlertDeliveries).
+		Path("/alert-deliveries").
+		WithOpID("alert_deliveries"))
+
+	grp.Register(mason.HandlePost(a.TestNotification).
+		Path("/notification-channels/test").
+		WithOpID("test_notification").
+		WithSuccessCode(http.StatusOK))
+
+	grp.Register(mason.HandleGet(a.NotificationChannels).
+		Path("/notification-channels").
+		WithOpID("notification_channels"))
+
+	grp.Register(mason.HandlePut(a.UpdateNotificationChannel).
+		Path("/notification-channels").
+		WithOpID("update_notification_channel"))
+
+	grp.Register(mason.HandleDelete(a.DeleteNotificationChannel).
+		Path("/notification-channels/{name}").
+		WithOpID("delete_notification_channel").
+		WithSuccessCode(http.StatusOK))
+
+	grp.Register(mason.HandleGet(a.Silences).
+		Path("/silences").
+		WithOpID("silences"))
+
+	grp.Register(mason.HandlePost(a.CreateSilence).
+		Path("/silences").
+		WithOpID("create_silence"))
+
+	grp.Register(mason.HandlePost(a.ExpireSilence).
+		Path("/silences/{id}/expire").
+		WithOpID("expire_silence").
+		WithSuccessCode(http.StatusOK))
 }
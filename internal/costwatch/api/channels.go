@@ -0,0 +1,337 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/app"
+	notinfr "github.com/tailbits/costwatch/internal/costwatch/infra/notifier"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/web"
+)
+
+var errNoChannels = errors.New("notification channels require SQLite")
+
+// UpdateNotificationChannelRequest is the payload to create or replace a
+// notification channel. Config depends on Type:
+//   - webhook: url, and optionally format, headers and secret;
+//   - pagerduty: routing_key, and optionally events_url;
+//   - email: host, from, and optionally port, username, password and to.
+//
+// Masked secrets, as returned by the API, keep the stored ones.
+type UpdateNotificationChannelRequest struct {
+	ChannelName string          `json:"name"`
+	Type        string          `json:"type"`
+	Config      json.RawMessage `json:"config"`
+}
+
+var _ model.Entity = (*UpdateNotificationChannelRequest)(nil)
+
+//go:embed schemas/update_notification_channel_payload.schema.json
+var updateNotificationChannelPayloadSchema []byte
+
+//go:embed schemas/update_notification_channel_payload.example.json
+var updateNotificationChannelPayloadExample []byte
+
+func (r *UpdateNotificationChannelRequest) Name() string {
+	return "UpdateNotificationChannelRequest"
+}
+func (r *UpdateNotificationChannelRequest) Schema() []byte {
+	return updateNotificationChannelPayloadSchema
+}
+func (r *UpdateNotificationChannelRequest) Example() []byte {
+	return updateNotificationChannelPayloadExample
+}
+func (r *UpdateNotificationChannelRequest) Marshal() (json.RawMessage, error) {
+	return json.Marshal(r)
+}
+func (r *UpdateNotificationChannelRequest) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+var _ model.Entity = (*NotificationChannel)(nil)
+
+//go:embed schemas/notification_channel_response.schema.json
+var notificationChannelResponseSchema []byte
+
+//go:embed schemas/notification_channel_response.example.json
+var notificationChannelResponseExample []byte
+
+func (c *NotificationChannel) Name() string                      { return "NotificationChannel" }
+func (c *NotificationChannel) Schema() []byte                    { return notificationChannelResponseSchema }
+func (c *NotificationChannel) Example() []byte                   { return notificationChannelResponseExample }
+func (c *NotificationChannel) Marshal() (json.RawMessage, error) { return json.Marshal(c) }
+func (c *NotificationChannel) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, c)
+}
+
+type NotificationChannelsResponse ListResult[NotificationChannel]
+
+var _ model.Entity = (*NotificationChannelsResponse)(nil)
+
+//go:embed schemas/notification_channels_response.schema.json
+var notificationChannelsResponseSchema []byte
+
+//go:embed schemas/notification_channels_response.example.json
+var notificationChannelsResponseExample []byte
+
+func (r *NotificationChannelsResponse) Name() string                      { return "NotificationChannelsResponse" }
+func (r *NotificationChannelsResponse) Schema() []byte                    { return notificationChannelsResponseSchema }
+func (r *NotificationChannelsResponse) Example() []byte                   { return notificationChannelsResponseExample }
+func (r *NotificationChannelsResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *NotificationChannelsResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// NotificationChannels lists the notification channels by name.
+func (a *API) NotificationChannels(ctx context.Context, _ *http.Request, _ model.Nil) (res *NotificationChannelsResponse, err error) {
+	items := make([]NotificationChannel, 0)
+	if a.channels == nil {
+		return &NotificationChannelsResponse{Items: items}, nil
+	}
+	recs, err := a.channels.ListChannels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("channels.List: %w", err)
+	}
+	for _, ch := range recs {
+		out, err := newNotificationChannel(ch)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out)
+	}
+	return &NotificationChannelsResponse{Items: items}, nil
+}
+
+// UpdateNotificationChannel creates a notification channel, or replaces the
+// type and config of the channel with the same name. The worker picks up
+// changes on its next run.
+func (a *API) UpdateNotificationChannel(ctx context.Context, _ *http.Request, ent *UpdateNotificationChannelRequest, _ model.Nil) (res *NotificationChannel, err error) {
+	if a.channels == nil {
+		return nil, errNoChannels
+	}
+	invalid := func(msg string) error {
+		return model.ValidationError{Errors: []model.FieldError{{Message: msg}}}
+	}
+	if err := app.ValidateChannelName(ent.ChannelName); err != nil {
+		return nil, invalid(err.Error())
+	}
+
+	now := time.Now().UTC()
+	ch := port.Channel{
+		Name:      ent.ChannelName,
+		Type:      port.ChannelType(ent.Type),
+		Config:    ent.Config,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	prev, ok, err := a.channels.GetChannel(ctx, ch.Name)
+	if err != nil {
+		return nil, fmt.Errorf("channels.Get: %w", err)
+	}
+	if ok {
+		ch.CreatedAt = prev.CreatedAt
+		if ch.Config, err = notinfr.KeepChannelSecrets(ch, prev); err != nil {
+			return nil, invalid(err.Error())
+		}
+	}
+	if _, err := notinfr.NewChannelNotifier(ch, nil); err != nil {
+		return nil, invalid(err.Error())
+	}
+
+	if err := a.channels.UpsertChannel(ctx, ch); err != nil {
+		return nil, fmt.Errorf("channels.Upsert: %w", err)
+	}
+	out, err := newNotificationChannel(ch)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteNotificationChannel deletes a notification channel that no rule uses.
+func (a *API) DeleteNotificationChannel(ctx context.Context, r *http.Request, _ model.Nil, _ model.Nil) (res *NotificationChannel, err error) {
+	if a.channels == nil {
+		return nil, web.ErrNotFound
+	}
+	ch, ok, err := a.channels.GetChannel(ctx, web.Param(r, "name"))
+	if err != nil {
+		return nil, fmt.Errorf("channels.Get: %w", err)
+	}
+	if !ok {
+		return nil, web.ErrNotFound
+	}
+
+	rules, err := a.alert.Alerts.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rules.List: %w", err)
+	}
+	var users []string
+	for _, rule := range rules {
+		if slices.Contains(rule.Channels, ch.Name) {
+			users = append(users, rule.Service+"/"+rule.Metric)
+		}
+	}
+	if len(users) > 0 {
+		msg := fmt.Sprintf("channel %s is used by the rules of %s", ch.Name, strings.Join(users, ", "))
+		return nil, model.ValidationError{Errors: []model.FieldError{{Message: msg}}}
+	}
+
+	if err := a.channels.DeleteChannel(ctx, ch.Name); err != nil {
+		return nil, fmt.Errorf("channels.Delete: %w", err)
+	}
+	out, err := newNotificationChannel(ch)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestNotificationRequest is the payload to send a test notification to
+// Channel, or to the default channel of Severity if empty.
+type TestNotificationRequest struct {
+	Channel  string `json:"channel,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	State    string `json:"state,omitempty"`
+}
+
+var _ model.Entity = (*TestNotificationRequest)(nil)
+
+//go:embed schemas/test_notification_payload.schema.json
+var testNotificationPayloadSchema []byte
+
+//go:embed schemas/test_notification_payload.example.json
+var testNotificationPayloadExample []byte
+
+func (r *TestNotificationRequest) Name() string                      { return "TestNotificationRequest" }
+func (r *TestNotificationRequest) Schema() []byte                    { return testNotificationPayloadSchema }
+func (r *TestNotificationRequest) Example() []byte                   { return testNotificationPayloadExample }
+func (r *TestNotificationRequest) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *TestNotificationRequest) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+var _ model.Entity = (*TestNotificationResponse)(nil)
+
+//go:embed schemas/test_notification_response.schema.json
+var testNotificationResponseSchema []byte
+
+//go:embed schemas/test_notification_response.example.json
+var testNotificationResponseExample []byte
+
+func (r *TestNotificationResponse) Name() string                      { return "TestNotificationResponse" }
+func (r *TestNotificationResponse) Schema() []byte                    { return testNotificationResponseSchema }
+func (r *TestNotificationResponse) Example() []byte                   { return testNotificationResponseExample }
+func (r *TestNotificationResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *TestNotificationResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// TestNotification sends a sample alert through a notification channel and
+// reports whether it was delivered.
+func (a *API) TestNotification(ctx context.Context, _ *http.Request, ent *TestNotificationRequest, _ model.Nil) (res *TestNotificationResponse, err error) {
+	return a.SendTestNotification(ctx, ent.Channel, port.Severity(ent.Severity), port.AlertState(ent.State))
+}
+
+// SendTestNotification sends a sample alert of sev and state to channel, or
+// to the default channel of sev if empty, with the notifiers the worker would
+// use: those of the env and the notification channels.
+func (a *API) SendTestNotification(ctx context.Context, channel string, sev port.Severity, state port.AlertState) (*TestNotificationResponse, error) {
+	invalid := func(msg string) error {
+		return model.ValidationError{Errors: []model.FieldError{{Message: msg}}}
+	}
+	if sev != "" && sev != port.SeverityWarning && sev != port.SeverityCritical {
+		return nil, invalid(fmt.Sprintf("severity must be warning or critical, got %q", sev))
+	}
+	if state != "" && state != port.AlertFiring && state != port.AlertResolved {
+		return nil, invalid(fmt.Sprintf("state must be firing or resolved, got %q", state))
+	}
+
+	tmpl, err := notinfr.TemplatesFromEnv()
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	// A notification channel does not depend on the env notifiers, which are
+	// only built to test the default channels.
+	svc := app.NewAlertService(nil, nil, nil, nil)
+	if channel != "" && a.channels != nil {
+		ch, ok, err := a.channels.GetChannel(ctx, channel)
+		if err != nil {
+			return nil, fmt.Errorf("channels.Get: %w", err)
+		}
+		if ok {
+			n, err := notinfr.NewChannelNotifier(ch, tmpl)
+			if err != nil {
+				return nil, invalid(err.Error())
+			}
+			svc.Channels = map[string]port.Notifier{ch.Name: n}
+		}
+	}
+	if channel == "" || (svc.Channels == nil && app.IsDefaultChannel(channel)) {
+		n, name, err := notinfr.NewFromEnv(tmpl)
+		if err != nil {
+			return nil, invalid(err.Error())
+		}
+		svc.Notify = n
+		svc.NotifyChannel = name
+		svc.SeverityNotifiers = notinfr.NewSeverityNotifiersFromEnv(tmpl)
+	}
+
+	out, err := svc.TestNotification(ctx, channel, sev, state)
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	return &TestNotificationResponse{
+		Channel:    out.Channel,
+		Delivered:  out.Delivered,
+		StatusCode: out.StatusCode,
+		Response:   out.Response,
+		Error:      out.Error,
+	}, nil
+}
+
+// checkChannels reports the channels of a rule that do not exist.
+func (a *API) checkChannels(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if a.channels == nil {
+		return model.ValidationError{Errors: []model.FieldError{{Message: errNoChannels.Error()}}}
+	}
+	var errs []model.FieldError
+	for _, name := range names {
+		_, ok, err := a.channels.GetChannel(ctx, name)
+		if err != nil {
+			return fmt.Errorf("channels.Get: %w", err)
+		}
+		if !ok {
+			errs = append(errs, model.FieldError{Message: fmt.Sprintf("unknown notification channel %q", name)})
+		}
+	}
+	if len(errs) > 0 {
+		return model.ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func newNotificationChannel(ch port.Channel) (NotificationChannel, error) {
+	config, err := notinfr.RedactChannelConfig(ch)
+	if err != nil {
+		return NotificationChannel{}, fmt.Errorf("channel %s: %w", ch.Name, err)
+	}
+	return NotificationChannel{
+		ChannelName: ch.Name,
+		Type:        string(ch.Type),
+		Config:      config,
+		CreatedAt:   ch.CreatedAt,
+		UpdatedAt:   ch.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// maxDeliveryLimit caps the number of deliveries returned by a single request.
+const maxDeliveryLimit = 500
+
+type AlertDeliveriesResponse ListResult[AlertDelivery]
+
+var _ model.Entity = (*AlertDeliveriesResponse)(nil)
+
+//go:embed schemas/alert_deliveries_response.schema.json
+var alertDeliveriesResponseSchema []byte
+
+//go:embed schemas/alert_deliveries_response.example.json
+var alertDeliveriesResponseExample []byte
+
+func (r *AlertDeliveriesResponse) Name() string                      { return "AlertDeliveriesResponse" }
+func (r *AlertDeliveriesResponse) Schema() []byte                    { return alertDeliveriesResponseSchema }
+func (r *AlertDeliveriesResponse) Example() []byte                   { return alertDeliveriesResponseExample }
+func (r *AlertDeliveriesResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *AlertDeliveriesResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// AlertDeliveriesParams are the query parameters accepted by the alert
+// deliveries endpoint.
+type AlertDeliveriesParams struct {
+	// Status is "pending", "failed" or "delivered" (default: pending and
+	// failed).
+	Status string `json:"status"`
+	// Limit is the maximum number of deliveries returned (default: 100).
+	Limit int `json:"limit"`
+}
+
+func (p AlertDeliveriesParams) filter() (port.DeliveryFilter, error) {
+	f := port.DeliveryFilter{Status: port.DeliveryStatus(p.Status), Limit: p.Limit}
+	var errs []model.FieldError
+	switch f.Status {
+	case "", port.DeliveryPending, port.DeliveryFailed, port.DeliveryDelivered:
+	default:
+		errs = append(errs, model.FieldError{Message: "status must be pending, failed or delivered"})
+	}
+	if f.Limit < 0 || f.Limit > maxDeliveryLimit {
+		errs = append(errs, model.FieldError{Message: fmt.Sprintf("limit must be between 1 and %d", maxDeliveryLimit)})
+	}
+	if len(errs) > 0 {
+		return f, model.ValidationError{Errors: errs}
+	}
+	return f, nil
+}
+
+// AlertDeliveries lists the notifications of the outbox, most recent first:
+// by default those still pending and those that failed.
+func (a *API) AlertDeliveries(ctx context.Context, _ *http.Request, params AlertDeliveriesParams) (res *AlertDeliveriesResponse, err error) {
+	f, err := params.filter()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]AlertDelivery, 0)
+	if a.outbox != nil {
+		recs, err := a.outbox.ListDeliveries(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("outbox.List: %w", err)
+		}
+		for _, d := range recs {
+			items = append(items, newAlertDelivery(d))
+		}
+	}
+	return &AlertDeliveriesResponse{Items: items}, nil
+}
+
+func newAlertDelivery(d port.Delivery) AlertDelivery {
+	return AlertDelivery{
+		ID:            d.ID,
+		IncidentID:    d.IncidentID,
+		Channel:       d.Channel,
+		State:         string(d.Event.State),
+		Severity:      string(d.Event.Severity),
+		Summary:       d.Event.Summary(),
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt,
+		NextAttemptAt: timePtr(d.NextAttemptAt),
+		DeliveredAt:   timePtr(d.DeliveredAt),
+	}
+}
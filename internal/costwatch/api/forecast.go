@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+type ForecastResponse QueryResult[ForecastRecord]
+
+var _ model.Entity = (*ForecastResponse)(nil)
+
+//go:embed schemas/forecast_response.schema.json
+var forecastResponseSchema []byte
+
+//go:embed schemas/forecast_response.example.json
+var forecastResponseExample []byte
+
+func (r *ForecastResponse) Name() string                      { return "ForecastResponse" }
+func (r *ForecastResponse) Schema() []byte                    { return forecastResponseSchema }
+func (r *ForecastResponse) Example() []byte                   { return forecastResponseExample }
+func (r *ForecastResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *ForecastResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// ForecastParams are the query parameters accepted by the forecast endpoint.
+type ForecastParams struct {
+	// Service and Metric restrict the forecast to a single service/metric.
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+	// Account and Region restrict the forecast to a single AWS account or region.
+	Account string `json:"account"`
+	Region  string `json:"region"`
+}
+
+// Forecast projects the spend of the current billing month per service/metric.
+func (a *API) Forecast(ctx context.Context, _ *http.Request, params ForecastParams) (res *ForecastResponse, err error) {
+	now := time.Now().UTC()
+	q := port.MetricsQuery{
+		Service: params.Service,
+		Metric:  params.Metric,
+		Account: params.Account,
+		Region:  params.Region,
+	}
+	recs, err := a.forecast.MonthEnd(ctx, now, q)
+	if err != nil {
+		return nil, fmt.Errorf("forecast.MonthEnd: %w", err)
+	}
+
+	items := make([]ForecastRecord, 0, len(recs))
+	for _, f := range recs {
+		items = append(items, ForecastRecord{
+			Service:     f.Service,
+			Metric:      f.Metric,
+			MonthToDate: f.Actual,
+			Forecast:    f.Projected,
+			Lower:       f.Lower,
+			Upper:       f.Upper,
+		})
+	}
+
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	res = &ForecastResponse{
+		Items:    items,
+		FromDate: start,
+		ToDate:   start.AddDate(0, 1, 0),
+		Interval: 86400,
+	}
+	return res, nil
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/web"
+)
+
+// maxIncidentLimit caps the number of incidents returned by a single request.
+const maxIncidentLimit = 500
+
+type AlertIncidentsResponse ListResult[AlertIncident]
+
+var _ model.Entity = (*AlertIncidentsResponse)(nil)
+
+//go:embed schemas/alert_incidents_response.schema.json
+var alertIncidentsResponseSchema []byte
+
+//go:embed schemas/alert_incidents_response.example.json
+var alertIncidentsResponseExample []byte
+
+func (r *AlertIncidentsResponse) Name() string                      { return "AlertIncidentsResponse" }
+func (r *AlertIncidentsResponse) Schema() []byte                    { return alertIncidentsResponseSchema }
+func (r *AlertIncidentsResponse) Example() []byte                   { return alertIncidentsResponseExample }
+func (r *AlertIncidentsResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *AlertIncidentsResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+type AlertIncidentResponse AlertIncident
+
+var _ model.Entity = (*AlertIncidentResponse)(nil)
+
+//go:embed schemas/alert_incident_response.schema.json
+var alertIncidentResponseSchema []byte
+
+//go:embed schemas/alert_incident_response.example.json
+var alertIncidentResponseExample []byte
+
+func (r *AlertIncidentResponse) Name() string                      { return "AlertIncidentResponse" }
+func (r *AlertIncidentResponse) Schema() []byte                    { return alertIncidentResponseSchema }
+func (r *AlertIncidentResponse) Example() []byte                   { return alertIncidentResponseExample }
+func (r *AlertIncidentResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *AlertIncidentResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// AckAlertIncidentRequest is the payload to acknowledge an incident.
+type AckAlertIncidentRequest struct {
+	By string `json:"by"`
+}
+
+var _ model.Entity = (*AckAlertIncidentRequest)(nil)
+
+//go:embed schemas/ack_alert_incident_payload.schema.json
+var ackAlertIncidentPayloadSchema []byte
+
+//go:embed schemas/ack_alert_incident_payload.example.json
+var ackAlertIncidentPayloadExample []byte
+
+func (r *AckAlertIncidentRequest) Name() string                      { return "AckAlertIncidentRequest" }
+func (r *AckAlertIncidentRequest) Schema() []byte                    { return ackAlertIncidentPayloadSchema }
+func (r *AckAlertIncidentRequest) Example() []byte                   { return ackAlertIncidentPayloadExample }
+func (r *AckAlertIncidentRequest) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *AckAlertIncidentRequest) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// AlertIncidentsParams are the query parameters accepted by the alert incidents endpoint.
+type AlertIncidentsParams struct {
+	// Status is "open" or "resolved" (default: both).
+	Status string `json:"status"`
+	// Service and Metric restrict incidents to a single service/metric.
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+	// From and To bound the start of the incidents as RFC 3339 timestamps.
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Limit is the maximum number of incidents returned (default: 100).
+	Limit int `json:"limit"`
+}
+
+func (p AlertIncidentsParams) filter() (port.IncidentFilter, error) {
+	f := port.IncidentFilter{
+		Status:  port.IncidentStatus(p.Status),
+		Service: p.Service,
+		Metric:  p.Metric,
+		Limit:   p.Limit,
+	}
+
+	var errs []model.FieldError
+	switch f.Status {
+	case "", port.IncidentOpen, port.IncidentResolved:
+	default:
+		errs = append(errs, model.FieldError{Message: "status must be open or resolved"})
+	}
+	if p.From != "" {
+		t, err := time.Parse(time.RFC3339, p.From)
+		if err != nil {
+			errs = append(errs, model.FieldError{Message: "from must be an RFC 3339 timestamp"})
+		}
+		f.From = t
+	}
+	if p.To != "" {
+		t, err := time.Parse(time.RFC3339, p.To)
+		if err != nil {
+			errs = append(errs, model.FieldError{Message: "to must be an RFC 3339 timestamp"})
+		}
+		f.To = t
+	}
+	if f.Limit < 0 || f.Limit > maxIncidentLimit {
+		errs = append(errs, model.FieldError{Message: fmt.Sprintf("limit must be between 1 and %d", maxIncidentLimit)})
+	}
+
+	if len(errs) > 0 {
+		return f, model.ValidationError{Errors: errs}
+	}
+	return f, nil
+}
+
+// AlertIncidents lists alert incidents, most recently started first.
+func (a *API) AlertIncidents(ctx context.Context, _ *http.Request, params AlertIncidentsParams) (res *AlertIncidentsResponse, err error) {
+	f, err := params.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]AlertIncident, 0)
+	if a.incidents != nil {
+		recs, err := a.incidents.ListIncidents(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("incidents.List: %w", err)
+		}
+		for _, inc := range recs {
+			items = append(items, newAlertIncident(inc))
+		}
+	}
+
+	return &AlertIncidentsResponse{Items: items}, nil
+}
+
+// AlertIncident returns an alert incident with the notifications sent for it.
+func (a *API) AlertIncident(ctx context.Context, r *http.Request, _ model.Nil) (res *AlertIncidentResponse, err error) {
+	id, err := strconv.ParseInt(web.Param(r, "id"), 10, 64)
+	if err != nil || a.incidents == nil {
+		return nil, web.ErrNotFound
+	}
+	return a.alertIncident(ctx, id)
+}
+
+// AckAlertIncident acknowledges an open incident: its window is not notified
+// again, until the rule fires for a new window.
+func (a *API) AckAlertIncident(ctx context.Context, r *http.Request, ent *AckAlertIncidentRequest, _ model.Nil) (res *AlertIncidentResponse, err error) {
+	id, err := strconv.ParseInt(web.Param(r, "id"), 10, 64)
+	if err != nil || a.incidents == nil {
+		return nil, web.ErrNotFound
+	}
+	inc, ok, err := a.incidents.GetIncident(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("incidents.Get: %w", err)
+	}
+	if !ok {
+		return nil, web.ErrNotFound
+	}
+	if inc.Status != port.IncidentOpen {
+		return nil, model.ValidationError{Errors: []model.FieldError{{Message: "only open incidents can be acknowledged"}}}
+	}
+	if err := a.incidents.Acknowledge(ctx, id, ent.By, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("incidents.Acknowledge: %w", err)
+	}
+	return a.alertIncident(ctx, id)
+}
+
+func (a *API) alertIncident(ctx context.Context, id int64) (*AlertIncidentResponse, error) {
+	inc, ok, err := a.incidents.GetIncident(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("incidents.Get: %w", err)
+	}
+	if !ok {
+		return nil, web.ErrNotFound
+	}
+
+	out := newAlertIncident(inc)
+	out.Notifications = make([]AlertNotification, 0, len(inc.Notifications))
+	for _, n := range inc.Notifications {
+		out.Notifications = append(out.Notifications, AlertNotification{
+			SentAt:  n.SentAt,
+			Channel: n.Channel,
+			Message: n.Message,
+			Error:   n.Error,
+		})
+	}
+	return (*AlertIncidentResponse)(&out), nil
+}
+
+func newAlertIncident(inc port.Incident) AlertIncident {
+	return AlertIncident{
+		ID:         inc.ID,
+		Service:    inc.Service,
+		Metric:     inc.Metric,
+		Kind:       string(inc.Kind),
+		Period:     string(inc.Period),
+		Dimension:  inc.Dimension,
+		Status:     string(inc.Status),
+		Severity:   string(inc.Severity),
+		StartedAt:  inc.StartedAt,
+		UpdatedAt:  inc.UpdatedAt,
+		ResolvedAt: timePtr(inc.ResolvedAt),
+		PeakCost:   inc.PeakCost,
+		Overspend:  inc.Overspend,
+		Summary:    inc.Summary,
+		AckedAt:    timePtr(inc.AckedAt),
+		AckedBy:    inc.AckedBy,
+	}
+}
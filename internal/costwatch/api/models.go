@@ -20,22 +20,129 @@ type ListResult[T any] struct {
 type Record struct {
 	Service   string    `json:"service"`
 	Metric    string    `json:"metric"`
+	Dimension string    `json:"dimension,omitempty"`
 	Cost      float64   `json:"cost"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
 // AlertWindow represents a contiguous time window where the hourly cost exceeded
-// the configured threshold for a given service/metric. ExpectedCost is the
-// (threshold * hours in window). RealCost is the sum of costs in the window.
+// the configured threshold, or was anomalous, for a given service/metric. Kind
+// is the kind of the breached rule, and Baseline describes what the rule
+// compared the hours against (e.g. "p90 over 7 days"). BaselineCost is the
+// average hourly baseline cost, and ExpectedCost the baseline cost of the whole
+// window ((threshold * hours in window) for threshold rules). RealCost is the
+// sum of costs in the window.
 // Diff is RealCost - ExpectedCost. DiffPercent is 100 * Diff / ExpectedCost
-// when ExpectedCost > 0, otherwise 0.
+// when ExpectedCost > 0, otherwise 0. Severity is the highest severity of the
+// hours of the window ("warning" or "critical"). Silenced is true when a
+// silence was in effect during the window. AckedAt and AckedBy are set once
+// the incident of the window was acknowledged.
 type AlertWindow struct {
 	Service      string     `json:"service"`
 	Metric       string     `json:"metric"`
+	Dimension    string     `json:"dimension,omitempty"`
+	Kind         string     `json:"kind"`
+	Baseline     string     `json:"baseline"`
 	Start        time.Time  `json:"start"`
 	End          *time.Time `json:"end"`
+	BaselineCost float64    `json:"baseline_cost"`
 	ExpectedCost float64    `json:"expected_cost"`
 	RealCost     float64    `json:"real_cost"`
+	Severity     string     `json:"severity"`
+	Silenced     bool       `json:"silenced"`
+	AckedAt      *time.Time `json:"acked_at"`
+	AckedBy      string     `json:"acked_by,omitempty"`
+}
+
+// AlertIncident is a rule that fired, from its first breach until it resolved.
+// Period is set for budgets, and Dimension for rules evaluated per dimension
+// value. PeakCost is the highest hourly cost (or period spend, for budgets),
+// and Overspend the cost above the rule's baseline or budget. Severity is the
+// highest severity the incident reached, including by escalation. ResolvedAt
+// is null while the incident is open, and AckedAt until it is acknowledged.
+// Notifications are only listed by the detail endpoint.
+type AlertIncident struct {
+	ID            int64               `json:"id"`
+	Service       string              `json:"service"`
+	Metric        string              `json:"metric"`
+	Kind          string              `json:"kind"`
+	Period        string              `json:"period,omitempty"`
+	Dimension     string              `json:"dimension,omitempty"`
+	Status        string              `json:"status"`
+	Severity      string              `json:"severity"`
+	StartedAt     time.Time           `json:"started_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	ResolvedAt    *time.Time          `json:"resolved_at"`
+	PeakCost      float64             `json:"peak_cost"`
+	Overspend     float64             `json:"overspend"`
+	Summary       string              `json:"summary"`
+	AckedAt       *time.Time          `json:"acked_at"`
+	AckedBy       string              `json:"acked_by,omitempty"`
+	Notifications []AlertNotification `json:"notifications,omitempty"`
+}
+
+// AlertNotification is a notification sent for an incident. Error is set when
+// the delivery failed.
+type AlertNotification struct {
+	SentAt  time.Time `json:"sent_at"`
+	Channel string    `json:"channel"`
+	Message string    `json:"message"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// AlertDelivery is a notification in the outbox. Status is "pending" until it
+// is delivered, or "failed" once it ran out of retries; LastError is the error
+// of the last failed attempt. NextAttemptAt is null once the delivery is over,
+// and DeliveredAt until it succeeded. IncidentID is 0 when incidents are not
+// tracked.
+type AlertDelivery struct {
+	ID            int64      `json:"id"`
+	IncidentID    int64      `json:"incident_id"`
+	Channel       string     `json:"channel"`
+	State         string     `json:"state"`
+	Severity      string     `json:"severity"`
+	Summary       string     `json:"summary"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
+
+// NotificationChannel is a named destination of notifications that rules
+// route their alerts to. Type is "webhook", "pagerduty" or "email", and Config
+// the settings of its notifier, with secrets masked.
+type NotificationChannel struct {
+	ChannelName string          `json:"name"`
+	Type        string          `json:"type"`
+	Config      json.RawMessage `json:"config"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Silence mutes the alerts of a service/metric from StartsAt until EndsAt.
+// An empty Service or Metric matches any.
+type Silence struct {
+	ID        int64     `json:"id"`
+	Service   string    `json:"service"`
+	Metric    string    `json:"metric"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ForecastRecord is the projected month-end spend of a service/metric. Lower
+// and Upper bound Forecast with a 90% confidence band.
+type ForecastRecord struct {
+	Service     string  `json:"service"`
+	Metric      string  `json:"metric"`
+	MonthToDate float64 `json:"month_to_date"`
+	Forecast    float64 `json:"forecast"`
+	Lower       float64 `json:"lower"`
+	Upper       float64 `json:"upper"`
 }
 
 type PercentileRecord struct {
@@ -47,10 +154,69 @@ type PercentileRecord struct {
 	PMax    float64 `json:"pmax"`
 }
 
+// PriceVersion is the price of a service/metric over an effective date range.
+// A null EffectiveFrom means the price has always applied; a null EffectiveTo
+// means it still applies.
+type PriceVersion struct {
+	Service       string      `json:"service"`
+	Metric        string      `json:"metric"`
+	EffectiveFrom *time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time  `json:"effective_to"`
+	Price         float64     `json:"price"`
+	UnitSize      float64     `json:"unit_size"`
+	Currency      string      `json:"currency"`
+	FreeUnits     float64     `json:"free_units"`
+	Tiers         []PriceTier `json:"tiers"`
+}
+
+type PriceTier struct {
+	UpTo  *float64 `json:"up_to"`
+	Price float64  `json:"price"`
+}
+
+// AlertRule is either an hourly cost threshold ("threshold", the default) or
+// a budget ("budget") of Amount per Period, notified as spend crosses each of
+// Percentages. Budgets compare the actual spend, or the spend forecast for the
+// end of the period when Basis is "forecast". Anomaly rules ("anomaly") fire
+// when an hour scores above Sensitivity against the same hour of the week in
+// the past BaselineWeeks weeks, scored with Method ("zscore" or "mad").
+// Baseline rules ("baseline") fire when an hour costs more than Percent above
+// the Percentile ("p50", "p90", "p95" or "max") of the previous BaselineDays
+// days, and change rules ("change") when it costs more than Percent above the
+// hour before. Open windows are notified again every RenotifyInterval seconds
+// (default: 3600) until they are acknowledged. Breaches above Critical, in the
+// unit of the rule's warning level (threshold, sensitivity, percent or budget
+// percentage), are critical rather than warnings, and so are windows open for
+// longer than EscalateAfter seconds. The email notifier also sends the alerts
+// of a rule to its Recipients. Alerts go to the notification Channels of the
+// rule, if any, instead of the default notifier.
 type AlertRule struct {
-	Service   string  `json:"service"`
-	Metric    string  `json:"metric"`
-	Threshold float64 `json:"threshold"`
+	Service     string    `json:"service"`
+	Metric      string    `json:"metric"`
+	Kind        string    `json:"kind,omitempty"`
+	Threshold   float64   `json:"threshold"`
+	Period      string    `json:"period,omitempty"`
+	Amount      float64   `json:"amount,omitempty"`
+	Percentages []float64 `json:"percentages,omitempty"`
+	Basis       string    `json:"basis,omitempty"`
+
+	Method        string  `json:"method,omitempty"`
+	Sensitivity   float64 `json:"sensitivity,omitempty"`
+	BaselineWeeks int     `json:"baseline_weeks,omitempty"`
+
+	Percent      float64 `json:"percent,omitempty"`
+	Percentile   string  `json:"percentile,omitempty"`
+	BaselineDays int     `json:"baseline_days,omitempty"`
+
+	RenotifyInterval int     `json:"renotify_interval,omitempty"`
+	Critical         float64 `json:"critical,omitempty"`
+	EscalateAfter    int     `json:"escalate_after,omitempty"`
+
+	Recipients []string `json:"recipients,omitempty"`
+	Channels   []string `json:"channels,omitempty"`
+
+	// set holds the fields of the payload the rule was read from.
+	set map[string]json.RawMessage
 }
 
 func (u *AlertRule) Name() string {
@@ -75,6 +241,49 @@ func (u *AlertRule) Unmarshal(data json.RawMessage) error {
 	return json.Unmarshal(data, u)
 }
 
+// UnmarshalJSON decodes a rule and records which fields the payload set.
+func (u *AlertRule) UnmarshalJSON(data []byte) error {
+	type rule AlertRule
+	if err := json.Unmarshal(data, (*rule)(u)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &u.set)
+}
+
+// mergeOnto returns prev with the fields set in the payload of u.
+func (u *AlertRule) mergeOnto(prev *AlertRule) (*AlertRule, error) {
+	data, err := json.Marshal(prev)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range u.set {
+		fields[k] = v
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return nil, err
+	}
+	var out AlertRule
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (u *AlertRule) Marshal() (json.RawMessage, error) {
 	return json.Marshal(u)
 }
+
+// TestNotificationResponse is the outcome of a test notification. StatusCode
+// and Response are the status and start of the body of an endpoint that
+// answered with a non-2xx status.
+type TestNotificationResponse struct {
+	Channel    string `json:"channel"`
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
 )
 
 type PercentilesResponse QueryResult[PercentileRecord]
@@ -41,12 +42,34 @@ func (r *PercentilesResponse) Unmarshal(data json.RawMessage) error {
 	return json.Unmarshal(data, r)
 }
 
-func (a *API) Percentiles(ctx context.Context, _ *http.Request, _ model.Nil) (res *PercentilesResponse, err error) {
-	end := time.Now().UTC()
-	start := end.Add(-7 * 24 * time.Hour)
-	interval := 3600
+// PercentilesParams are the query parameters accepted by the usage percentiles endpoint.
+type PercentilesParams struct {
+	// From and To bound the range as RFC 3339 timestamps (default: the last 7 days).
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Interval is the bucket size in seconds the percentiles are computed over (default: 3600).
+	Interval int `json:"interval"`
+	// Service and Metric restrict percentiles to a single service/metric.
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+	// Account and Region restrict percentiles to a single AWS account or region.
+	Account string `json:"account"`
+	Region  string `json:"region"`
+}
 
-	recs, err := a.usage.UsagePercentiles(ctx, start, end, time.Duration(interval)*time.Second)
+func (a *API) Percentiles(ctx context.Context, _ *http.Request, params PercentilesParams) (res *PercentilesResponse, err error) {
+	start, end, interval, err := queryRange(params.From, params.To, params.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	q := port.MetricsQuery{
+		Service: params.Service,
+		Metric:  params.Metric,
+		Account: params.Account,
+		Region:  params.Region,
+	}
+	recs, err := a.usage.UsagePercentiles(ctx, start, end, time.Duration(interval)*time.Second, q)
 	if err != nil {
 		return nil, fmt.Errorf("usage.UsagePercentiles: %w", err)
 	}
@@ -60,7 +83,7 @@ func (a *API) Percentiles(ctx context.Context, _ *http.Request, _ model.Nil) (re
 		Items:    items,
 		FromDate: start,
 		ToDate:   end,
-		Interval: 0,
+		Interval: interval,
 	}
 
 	return res, nil
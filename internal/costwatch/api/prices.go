@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/magicbell/mason/model"
+)
+
+type PriceVersionsResponse ListResult[PriceVersion]
+
+var _ model.Entity = (*PriceVersionsResponse)(nil)
+
+//go:embed schemas/price_versions_response.schema.json
+var priceVersionsResponseSchema []byte
+
+//go:embed schemas/price_versions_response.example.json
+var priceVersionsResponseExample []byte
+
+func (r *PriceVersionsResponse) Name() string                      { return "PriceVersionsResponse" }
+func (r *PriceVersionsResponse) Schema() []byte                    { return priceVersionsResponseSchema }
+func (r *PriceVersionsResponse) Example() []byte                   { return priceVersionsResponseExample }
+func (r *PriceVersionsResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *PriceVersionsResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// PriceVersionsParams are the query parameters accepted by the price versions endpoint.
+type PriceVersionsParams struct {
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+}
+
+// PriceVersions lists the price history of every service/metric, optionally
+// narrowed down to a single service and/or metric.
+func (a *API) PriceVersions(_ context.Context, _ *http.Request, params PriceVersionsParams) (res *PriceVersionsResponse, err error) {
+	items := make([]PriceVersion, 0)
+	for _, v := range a.usage.Catalog.PriceVersions() {
+		if params.Service != "" && v.Service != params.Service {
+			continue
+		}
+		if params.Metric != "" && v.Metric != params.Metric {
+			continue
+		}
+
+		tiers := make([]PriceTier, 0, len(v.Tiers))
+		for i, t := range v.Tiers {
+			tier := PriceTier{Price: t.Price}
+			if i < len(v.Tiers)-1 {
+				upTo := t.UpTo
+				tier.UpTo = &upTo
+			}
+			tiers = append(tiers, tier)
+		}
+
+		items = append(items, PriceVersion{
+			Service:       v.Service,
+			Metric:        v.Metric,
+			EffectiveFrom: timePtr(v.EffectiveFrom),
+			EffectiveTo:   timePtr(v.EffectiveTo),
+			Price:         v.Price,
+			UnitSize:      v.UnitSize,
+			Currency:      v.Currency,
+			FreeUnits:     v.FreeUnits,
+			Tiers:         tiers,
+		})
+	}
+
+	return &PriceVersionsResponse{Items: items}, nil
+}
+
+// timePtr returns nil for the zero time, so open-ended ranges serialize as null.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
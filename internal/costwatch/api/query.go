@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/magicbell/mason/model"
+)
+
+const (
+	defaultRange    = 7 * 24 * time.Hour
+	defaultInterval = 3600
+
+	// maxRange matches the retention of the metrics table.
+	maxRange    = 90 * 24 * time.Hour
+	minInterval = 60
+	maxInterval = 31 * 24 * 3600
+	maxBuckets  = 5000
+)
+
+// queryRange resolves the from/to/interval query parameters. from and to are
+// RFC 3339 timestamps; to defaults to now, from to 7 days before to, and the
+// interval (in seconds) to one hour. Invalid values are reported as a
+// model.ValidationError.
+func queryRange(from, to string, interval int) (start, end time.Time, _ int, err error) {
+	var errs []model.FieldError
+	invalid := func(format string, args ...any) {
+		errs = append(errs, model.FieldError{Message: fmt.Sprintf(format, args...)})
+	}
+
+	end = time.Now().UTC()
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			invalid("to must be an RFC 3339 timestamp")
+		} else {
+			end = t.UTC()
+		}
+	}
+
+	start = end.Add(-defaultRange)
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			invalid("from must be an RFC 3339 timestamp")
+		} else {
+			start = t.UTC()
+		}
+	}
+
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	if interval < minInterval || interval > maxInterval || interval%60 != 0 {
+		invalid("interval must be a multiple of 60 between %d and %d seconds", minInterval, maxInterval)
+	}
+
+	if len(errs) == 0 {
+		switch rng := end.Sub(start); {
+		case rng <= 0:
+			invalid("from must be before to")
+		case rng > maxRange:
+			invalid("the range from from to to must not exceed %d days", int(maxRange.Hours()/24))
+		case rng/(time.Duration(interval)*time.Second) > maxBuckets:
+			invalid("the range must not contain more than %d intervals", maxBuckets)
+		}
+	}
+
+	if len(errs) > 0 {
+		return start, end, interval, model.ValidationError{Errors: errs}
+	}
+	return start, end, interval, nil
+}
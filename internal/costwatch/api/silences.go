@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/web"
+)
+
+var errNoSilences = errors.New("silences require SQLite")
+
+// CreateSilenceRequest is the payload to create a silence. It lasts from
+// StartsAt (default: now) until EndsAt, or for Duration (a Go duration such as
+// "2h30m"). An empty Service or Metric matches any.
+type CreateSilenceRequest struct {
+	Service  string     `json:"service"`
+	Metric   string     `json:"metric"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+	Reason   string     `json:"reason"`
+	Author   string     `json:"author"`
+}
+
+var _ model.Entity = (*CreateSilenceRequest)(nil)
+
+//go:embed schemas/create_silence_payload.schema.json
+var createSilencePayloadSchema []byte
+
+//go:embed schemas/create_silence_payload.example.json
+var createSilencePayloadExample []byte
+
+func (r *CreateSilenceRequest) Name() string                      { return "CreateSilenceRequest" }
+func (r *CreateSilenceRequest) Schema() []byte                    { return createSilencePayloadSchema }
+func (r *CreateSilenceRequest) Example() []byte                   { return createSilencePayloadExample }
+func (r *CreateSilenceRequest) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *CreateSilenceRequest) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+var _ model.Entity = (*Silence)(nil)
+
+//go:embed schemas/silence_response.schema.json
+var silenceResponseSchema []byte
+
+//go:embed schemas/silence_response.example.json
+var silenceResponseExample []byte
+
+func (s *Silence) Name() string                      { return "Silence" }
+func (s *Silence) Schema() []byte                    { return silenceResponseSchema }
+func (s *Silence) Example() []byte                   { return silenceResponseExample }
+func (s *Silence) Marshal() (json.RawMessage, error) { return json.Marshal(s) }
+func (s *Silence) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, s)
+}
+
+type SilencesResponse ListResult[Silence]
+
+var _ model.Entity = (*SilencesResponse)(nil)
+
+//go:embed schemas/silences_response.schema.json
+var silencesResponseSchema []byte
+
+//go:embed schemas/silences_response.example.json
+var silencesResponseExample []byte
+
+func (r *SilencesResponse) Name() string                      { return "SilencesResponse" }
+func (r *SilencesResponse) Schema() []byte                    { return silencesResponseSchema }
+func (r *SilencesResponse) Example() []byte                   { return silencesResponseExample }
+func (r *SilencesResponse) Marshal() (json.RawMessage, error) { return json.Marshal(r) }
+func (r *SilencesResponse) Unmarshal(data json.RawMessage) error {
+	return json.Unmarshal(data, r)
+}
+
+// SilencesParams are the query parameters accepted by the silences endpoint.
+type SilencesParams struct {
+	// Expired also lists the silences that already ended.
+	Expired bool `json:"expired"`
+}
+
+// Silences lists the active and upcoming silences, most recently created first.
+func (a *API) Silences(ctx context.Context, _ *http.Request, params SilencesParams) (res *SilencesResponse, err error) {
+	items := make([]Silence, 0)
+	if a.silences == nil {
+		return &SilencesResponse{Items: items}, nil
+	}
+
+	var from time.Time
+	if !params.Expired {
+		from = time.Now().UTC()
+	}
+	recs, err := a.silences.ListSilences(ctx, from, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("silences.List: %w", err)
+	}
+	for _, s := range recs {
+		items = append(items, newSilence(s))
+	}
+	return &SilencesResponse{Items: items}, nil
+}
+
+// CreateSilence mutes the alerts of a service/metric for a while.
+func (a *API) CreateSilence(ctx context.Context, _ *http.Request, ent *CreateSilenceRequest, _ model.Nil) (res *Silence, err error) {
+	if a.silences == nil {
+		return nil, errNoSilences
+	}
+
+	now := time.Now().UTC()
+	sil := port.Silence{
+		Service:   ent.Service,
+		Metric:    ent.Metric,
+		StartsAt:  now,
+		Reason:    ent.Reason,
+		Author:    ent.Author,
+		CreatedAt: now,
+	}
+	if ent.StartsAt != nil {
+		sil.StartsAt = ent.StartsAt.UTC()
+	}
+
+	invalid := func(msg string) error {
+		return model.ValidationError{Errors: []model.FieldError{{Message: msg}}}
+	}
+	switch {
+	case ent.EndsAt != nil && ent.Duration != "":
+		return nil, invalid("set either ends_at or duration")
+	case ent.EndsAt != nil:
+		sil.EndsAt = ent.EndsAt.UTC()
+	case ent.Duration != "":
+		d, err := time.ParseDuration(ent.Duration)
+		if err != nil || d <= 0 {
+			return nil, invalid("duration must be a positive duration such as 2h or 30m")
+		}
+		sil.EndsAt = sil.StartsAt.Add(d)
+	default:
+		return nil, invalid("ends_at or duration is required")
+	}
+	if !sil.EndsAt.After(sil.StartsAt) || !sil.EndsAt.After(now) {
+		return nil, invalid("ends_at must be after starts_at and in the future")
+	}
+
+	id, err := a.silences.CreateSilence(ctx, sil)
+	if err != nil {
+		return nil, fmt.Errorf("silences.Create: %w", err)
+	}
+	sil.ID = id
+	out := newSilence(sil)
+	return &out, nil
+}
+
+// ExpireSilence ends a silence now.
+func (a *API) ExpireSilence(ctx context.Context, r *http.Request, _ model.Nil, _ model.Nil) (res *Silence, err error) {
+	id, err := strconv.ParseInt(web.Param(r, "id"), 10, 64)
+	if err != nil || a.silences == nil {
+		return nil, web.ErrNotFound
+	}
+	if err := a.silences.ExpireSilence(ctx, id, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("silences.Expire: %w", err)
+	}
+	sil, ok, err := a.silences.GetSilence(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("silences.Get: %w", err)
+	}
+	if !ok {
+		return nil, web.ErrNotFound
+	}
+	out := newSilence(sil)
+	return &out, nil
+}
+
+func newSilence(s port.Silence) Silence {
+	return Silence{
+		ID:        s.ID,
+		Service:   s.Service,
+		Metric:    s.Metric,
+		StartsAt:  s.StartsAt,
+		EndsAt:    s.EndsAt,
+		Reason:    s.Reason,
+		Author:    s.Author,
+		CreatedAt: s.CreatedAt,
+	}
+}
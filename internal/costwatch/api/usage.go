@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/magicbell/mason/model"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
 )
 
 type UsageResponse QueryResult[Record]
@@ -29,20 +30,46 @@ func (r *UsageResponse) Unmarshal(data json.RawMessage) error {
 	return json.Unmarshal(data, r)
 }
 
-// Usage returns service usage + cost per interval for the past 7 days.
-func (a *API) Usage(ctx context.Context, _ *http.Request, _ model.Nil) (res *UsageResponse, err error) {
-	end := time.Now().UTC()
-	start := end.Add(-7 * 24 * time.Hour)
-	interval := 3600
+// UsageParams are the query parameters accepted by the usage endpoint.
+type UsageParams struct {
+	// From and To bound the range as RFC 3339 timestamps (default: the last 7 days).
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Interval is the bucket size in seconds (default: 3600).
+	Interval int `json:"interval"`
+	// Service and Metric restrict costs to a single service/metric.
+	Service string `json:"service"`
+	Metric  string `json:"metric"`
+	// GroupBy breaks costs down per value of the given metric dimension (e.g. LogGroupName),
+	// or per AWS "account" or "region".
+	GroupBy string `json:"group_by"`
+	// Account and Region restrict costs to a single AWS account or region.
+	Account string `json:"account"`
+	Region  string `json:"region"`
+}
 
-	recs, err := a.usage.Usage(ctx, start, end, time.Duration(interval)*time.Second)
+// Usage returns service usage + cost per interval over the requested range.
+func (a *API) Usage(ctx context.Context, _ *http.Request, params UsageParams) (res *UsageResponse, err error) {
+	start, end, interval, err := queryRange(params.From, params.To, params.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	q := port.MetricsQuery{
+		GroupBy: params.GroupBy,
+		Service: params.Service,
+		Metric:  params.Metric,
+		Account: params.Account,
+		Region:  params.Region,
+	}
+	recs, err := a.usage.Usage(ctx, start, end, time.Duration(interval)*time.Second, q)
 	if err != nil {
 		return nil, fmt.Errorf("usage.Usage: %w", err)
 	}
 
 	items := make([]Record, 0, len(recs))
 	for _, it := range recs {
-		items = append(items, Record{Service: it.Service, Metric: it.Metric, Cost: it.Cost, Timestamp: it.Timestamp})
+		items = append(items, Record{Service: it.Service, Metric: it.Metric, Dimension: it.Dimension, Cost: it.Cost, Timestamp: it.Timestamp})
 	}
 
 	res = &UsageResponse{
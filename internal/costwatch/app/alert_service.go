@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"net/mail"
 	"sort"
 	"time"
 
@@ -10,133 +13,745 @@ import (
 )
 
 type AlertService struct {
-	Metrics port.MetricsRepo
-	Alerts  port.AlertsRepo
-	Notify  port.Notifier // optional for SendAlerts
-	Catalog port.Catalog
+	Metrics  port.MetricsRepo
+	Alerts   port.AlertsRepo
+	Notify   port.Notifier // optional for SendAlerts
+	Catalog  port.Catalog
+	Usage    *UsageService
+	Forecast *ForecastService
+	// Incidents records what SendAlerts notified, and Silences mute its
+	// notifications; both optional.
+	Incidents port.IncidentsRepo
+	Silences  port.SilencesRepo
+	// SeverityNotifiers route the notifications of a severity elsewhere than
+	// Notify; optional.
+	SeverityNotifiers map[port.Severity]port.Notifier
+	// NotifyChannel is the name notifications sent to Notify are recorded
+	// under; "webhook" when empty.
+	NotifyChannel string
+	// Channels are the named notification channels rules route their alerts
+	// to, instead of Notify; optional.
+	Channels map[string]port.Notifier
+	// Outbox keeps notifications until they are delivered, retrying failed
+	// deliveries; optional, notifications are sent once without it.
+	Outbox port.OutboxRepo
 }
 
 type AlertWindow struct {
-	Service   string
-	Metric    string
-	Start     time.Time
-	End       time.Time
-	Hours     int
-	RealCost  float64
-	Threshold float64
+	Service string
+	Metric  string
+	// Dimension is the MetricsQuery.GroupBy value the window was computed for.
+	Dimension string
+	// Kind is the kind of rule the window breached, and Baseline describes what
+	// the rule compared the buckets against.
+	Kind     port.RuleKind
+	Baseline string
+	Start    time.Time
+	End      time.Time
+	Hours    int
+	RealCost float64
+	PeakCost float64 // highest bucket cost
+	// Costs are the costs of the buckets of the window, in order.
+	Costs []float64
+	// Expected is the sum of the baseline costs of the buckets, and
+	// BaselineCost their average.
+	Expected     float64
+	BaselineCost float64
+	Threshold    float64 // hourly threshold of threshold rules
+	// Severity is the highest severity of the buckets of the window.
+	Severity port.Severity
+	// Rule is the rule the window breached.
+	Rule port.AlertRule
+	// Silenced reports that a silence was in effect during the window, and
+	// AckedAt and AckedBy who acknowledged its incident and when.
+	Silenced bool
+	AckedAt  time.Time
+	AckedBy  string
+}
+
+// BudgetStatus is the spend of a budget rule over its current period. Spend is
+// the actual or forecast spend, following the rule's basis, and Crossed is the
+// highest of the rule's percentages, or of its critical percentage, it reached
+// (0 if none). Severity is critical once the critical percentage is reached.
+type BudgetStatus struct {
+	Rule        port.AlertRule
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Spend       float64
+	Percent     float64
+	Crossed     float64
+	Severity    port.Severity
 }
 
 func NewAlertService(metrics port.MetricsRepo, alerts port.AlertsRepo, notifier port.Notifier, catalog port.Catalog) *AlertService {
-	return &AlertService{Metrics: metrics, Alerts: alerts, Notify: notifier, Catalog: catalog}
+	usage := NewUsageService(metrics, catalog)
+	return &AlertService{
+		Metrics:  metrics,
+		Alerts:   alerts,
+		Notify:   notifier,
+		Catalog:  catalog,
+		Usage:    usage,
+		Forecast: NewForecastService(usage),
+	}
+}
+
+// ValidateRule checks that a rule is complete and fills in the defaults of
+// every rule (an hourly renotify interval), of budget rules (the 50/80/100%
+// percentages and the actual spend basis), of anomaly rules (z-score, a
+// sensitivity of 3 and 4 weeks of baseline) and of baseline rules (the p90 of
+// the last 7 days).
+func ValidateRule(r *port.AlertRule) error {
+	if r.Kind == "" {
+		r.Kind = port.RuleThreshold
+	}
+	if r.RenotifyInterval == 0 {
+		r.RenotifyInterval = port.DefaultRenotifyInterval
+	}
+	if r.RenotifyInterval < time.Minute {
+		return fmt.Errorf("renotify_interval must be at least 60 seconds")
+	}
+	if r.Critical < 0 {
+		return fmt.Errorf("critical must not be negative")
+	}
+	if r.EscalateAfter != 0 && r.EscalateAfter < time.Minute {
+		return fmt.Errorf("escalate_after must be at least 60 seconds")
+	}
+	for i, it := range r.Recipients {
+		addr, err := mail.ParseAddress(it)
+		if err != nil {
+			return fmt.Errorf("recipients must be email addresses, got %q", it)
+		}
+		r.Recipients[i] = addr.Address
+	}
+	for _, it := range r.Channels {
+		if err := ValidateChannelName(it); err != nil {
+			return fmt.Errorf("channels: %w", err)
+		}
+	}
+	switch r.Kind {
+	case port.RuleThreshold:
+		if r.Service == "" || r.Metric == "" {
+			return fmt.Errorf("service and metric are required")
+		}
+		if r.Threshold < 0 {
+			return fmt.Errorf("threshold must not be negative")
+		}
+	case port.RuleBudget:
+		switch r.Period {
+		case port.PeriodDaily, port.PeriodWeekly, port.PeriodMonthly:
+		default:
+			return fmt.Errorf("period must be one of daily, weekly or monthly")
+		}
+		if r.Amount <= 0 {
+			return fmt.Errorf("amount must be positive")
+		}
+		if len(r.Percentages) == 0 {
+			r.Percentages = port.DefaultBudgetPercentages
+		}
+		for _, p := range r.Percentages {
+			if p <= 0 {
+				return fmt.Errorf("percentages must be positive")
+			}
+		}
+		if r.Basis == "" {
+			r.Basis = port.BasisActual
+		}
+		if r.Basis != port.BasisActual && r.Basis != port.BasisForecast {
+			return fmt.Errorf("basis must be actual or forecast")
+		}
+	case port.RuleAnomaly:
+		if r.Service == "" || r.Metric == "" {
+			return fmt.Errorf("service and metric are required")
+		}
+		if r.Method == "" {
+			r.Method = port.MethodZScore
+		}
+		if r.Method != port.MethodZScore && r.Method != port.MethodMAD {
+			return fmt.Errorf("method must be zscore or mad")
+		}
+		if r.Sensitivity == 0 {
+			r.Sensitivity = port.DefaultSensitivity
+		}
+		if r.Sensitivity < 0 {
+			return fmt.Errorf("sensitivity must be positive")
+		}
+		if r.BaselineWeeks == 0 {
+			r.BaselineWeeks = port.DefaultBaselineWeeks
+		}
+		if r.BaselineWeeks < 1 || r.BaselineWeeks > maxBaselineWeeks {
+			return fmt.Errorf("baseline_weeks must be between 1 and %d", maxBaselineWeeks)
+		}
+	case port.RuleBaseline, port.RuleChange:
+		if r.Service == "" || r.Metric == "" {
+			return fmt.Errorf("service and metric are required")
+		}
+		if r.Percent <= 0 {
+			return fmt.Errorf("percent must be positive")
+		}
+		if r.Kind == port.RuleChange {
+			break
+		}
+		if r.Percentile == "" {
+			r.Percentile = port.DefaultPercentile
+		}
+		switch r.Percentile {
+		case port.P50, port.P90, port.P95, port.PMax:
+		default:
+			return fmt.Errorf("percentile must be one of p50, p90, p95 or max")
+		}
+		if r.BaselineDays == 0 {
+			r.BaselineDays = port.DefaultBaselineDays
+		}
+		if r.BaselineDays < 1 || r.BaselineDays > maxBaselineDays {
+			return fmt.Errorf("baseline_days must be between 1 and %d", maxBaselineDays)
+		}
+	default:
+		return fmt.Errorf("kind must be threshold, budget, anomaly, baseline or change")
+	}
+	if r.Critical > 0 && r.Critical < warningLevel(*r) {
+		return fmt.Errorf("critical must not be below the warning level of the rule")
+	}
+	return nil
+}
+
+// warningLevel is the level Critical of a rule is compared with: the threshold,
+// sensitivity or percent of the rule, and none for budget rules, whose
+// percentages may lie on either side of Critical.
+func warningLevel(r port.AlertRule) float64 {
+	switch r.Kind {
+	case port.RuleAnomaly:
+		return r.Sensitivity
+	case port.RuleBaseline, port.RuleChange:
+		return r.Percent
+	case port.RuleBudget:
+		return 0
+	}
+	return r.Threshold
 }
 
 // ComputeWindows aggregates usage into buckets and returns contiguous windows
-// where the computed hourly cost exceeded configured thresholds.
-func (s *AlertService) ComputeWindows(ctx context.Context, start, end time.Time, bucket time.Duration) ([]AlertWindow, error) {
+// where buckets breached a threshold, anomaly, baseline or change rule. Each
+// bucket is costed with the price effective at its timestamp. Rules apply to
+// each q.GroupBy dimension value separately.
+//
+// Every rule compares a bucket against a baseline cost, reported as the
+// expected cost of the window:
+//   - threshold rules breach when the cost exceeds the threshold;
+//   - anomaly rules when the units score above the rule's sensitivity against
+//     the buckets at the same time of the week over the BaselineWeeks weeks
+//     before start (the baseline is their mean or median);
+//   - baseline rules when the cost is more than Percent above the Percentile
+//     of the bucket costs over the BaselineDays days before start;
+//   - change rules when the cost is more than Percent above the previous bucket.
+func (s *AlertService) ComputeWindows(ctx context.Context, start, end time.Time, bucket time.Duration, q port.MetricsQuery) ([]AlertWindow, error) {
 	rules, err := s.Alerts.ListRules(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("rules.List: %w", err)
 	}
-	if len(rules) == 0 {
-		return nil, nil
-	}
-	thr := make(map[string]float64, len(rules))
+
+	var (
+		active []port.AlertRule
+		weeks  int
+		days   = make(map[int]bool)
+		change bool
+	)
 	for _, r := range rules {
-		thr[r.Service+"\x00"+r.Metric] = r.Threshold
+		switch r.Kind {
+		case "", port.RuleThreshold, port.RuleAnomaly, port.RuleBaseline, port.RuleChange:
+		default:
+			continue
+		}
+		if err := ValidateRule(&r); err != nil {
+			return nil, fmt.Errorf("rule %s/%s: %w", r.Service, r.Metric, err)
+		}
+		switch r.Kind {
+		case port.RuleAnomaly:
+			weeks = max(weeks, r.BaselineWeeks)
+		case port.RuleBaseline:
+			days[r.BaselineDays] = true
+		case port.RuleChange:
+			change = true
+		}
+		active = append(active, r)
+	}
+	if len(active) == 0 {
+		return nil, nil
 	}
 
-	recs, err := s.Metrics.Aggregate(ctx, start, end, bucket)
+	// Change rules compare the first bucket with the one before start.
+	from := start
+	if change {
+		from = start.Add(-bucket)
+	}
+	recs, err := s.Metrics.Aggregate(ctx, from, end, bucket, q)
 	if err != nil {
 		return nil, fmt.Errorf("q.Aggregate: %w", err)
 	}
 
-	type curWin struct {
-		start time.Time
-		end   time.Time
-		hours int
-		real  float64
-		last  time.Time
-		thr   float64
+	// Buckets are priced like in Usage, after the units already used in their
+	// billing month, so free allowances are only taken off once a month.
+	pos, err := s.Usage.billingPositions(ctx, from, end, bucket, recs, q)
+	if err != nil {
+		return nil, err
+	}
+
+	in := ruleInputs{
+		start:       start,
+		bucket:      bucket,
+		pos:         pos,
+		percentiles: make(map[int]map[string]port.MetricPercentiles, len(days)),
+	}
+
+	// Baselines are learned once over the longest BaselineWeeks; rules with
+	// fewer weeks read the same summary, which keeps this to a single query.
+	if weeks > 0 {
+		bs, err := s.Metrics.Baseline(ctx, start.Add(-time.Duration(weeks)*week), start, bucket, q)
+		if err != nil {
+			return nil, fmt.Errorf("q.Baseline: %w", err)
+		}
+		in.baselines = make(map[string]port.MetricBaseline, len(bs))
+		for _, b := range bs {
+			in.baselines[baselineKey(b.Service, b.Metric, b.Dimension, b.Slot)] = b
+		}
+	}
+
+	for n := range days {
+		ps, err := s.Metrics.Percentiles(ctx, start.Add(-time.Duration(n)*day), start, bucket, q)
+		if err != nil {
+			return nil, fmt.Errorf("q.Percentiles: %w", err)
+		}
+		byKey := make(map[string]port.MetricPercentiles, len(ps))
+		for _, p := range ps {
+			byKey[p.Service+"\x00"+p.Metric+"\x00"+p.Dimension] = p
+		}
+		in.percentiles[n] = byKey
+	}
+
+	windows := make([]AlertWindow, 0)
+	for _, r := range active {
+		windows = append(windows, s.ruleWindows(r, recs, in)...)
+	}
+
+	if err := s.annotateWindows(ctx, start, end, windows); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.After(windows[j].Start) })
+	return windows, nil
+}
+
+// annotateWindows marks the windows during which a silence was in effect, and
+// those whose incident was acknowledged.
+func (s *AlertService) annotateWindows(ctx context.Context, start, end time.Time, windows []AlertWindow) error {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	if s.Silences != nil {
+		sils, err := s.Silences.ListSilences(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("silences.List: %w", err)
+		}
+		for i, w := range windows {
+			for _, sil := range sils {
+				if sil.Matches(w.Service, w.Metric) && sil.Overlaps(w.Start, w.End) {
+					windows[i].Silenced = true
+					break
+				}
+			}
+		}
+	}
+
+	if s.Incidents != nil {
+		incs, err := s.Incidents.ListBetween(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("incidents.ListBetween: %w", err)
+		}
+		for i, w := range windows {
+			key := windowIncidentKey(w)
+			for _, inc := range incs {
+				if inc.IncidentKey != key || inc.AckedAt.IsZero() || !inc.StartedAt.Before(w.End) {
+					continue
+				}
+				if inc.ResolvedAt.IsZero() || inc.ResolvedAt.After(w.Start) {
+					windows[i].AckedAt = inc.AckedAt
+					windows[i].AckedBy = inc.AckedBy
+				}
+			}
+		}
 	}
 
+	return nil
+}
+
+const (
+	week = 7 * day
+
+	// maxBaselineWeeks keeps anomaly baselines within the metrics retention.
+	maxBaselineWeeks = 12
+	// maxBaselineDays keeps percentile baselines within the metrics retention.
+	maxBaselineDays = 84
+	// minBaselineSamples is the number of past weeks needed to score a bucket.
+	minBaselineSamples = 2
+	// madScale makes the median absolute deviation comparable to a standard
+	// deviation for normally distributed data.
+	madScale = 1.4826
+)
+
+// ruleInputs holds what ComputeWindows loaded to evaluate rules.
+type ruleInputs struct {
+	start       time.Time
+	bucket      time.Duration
+	pos         []billingPosition                         // of each bucket
+	baselines   map[string]port.MetricBaseline            // by baselineKey
+	percentiles map[int]map[string]port.MetricPercentiles // by BaselineDays, then service/metric/dimension
+}
+
+func baselineKey(service, metric, dimension string, slot int64) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%d", service, metric, dimension, slot)
+}
+
+// ruleWindows returns the windows of consecutive breaching buckets of a rule.
+// recs must be ordered by service, metric, dimension and time, as returned by
+// MetricsRepo.Aggregate.
+func (s *AlertService) ruleWindows(r port.AlertRule, recs []port.MetricBucket, in ruleInputs) []AlertWindow {
 	var (
-		windows    = make([]AlertWindow, 0)
-		curService string
-		curMetric  string
-		cur        *curWin
-		curKey     string
+		windows []AlertWindow
+		cur     *AlertWindow
+		last    time.Time
+		prev    *port.MetricBucket
 	)
-
 	flush := func() {
-		if cur == nil || cur.hours == 0 {
-			return
-		}
-		windows = append(windows, AlertWindow{
-			Service:   curService,
-			Metric:    curMetric,
-			Start:     cur.start,
-			End:       cur.end,
-			Hours:     cur.hours,
-			RealCost:  cur.real,
-			Threshold: cur.thr,
-		})
-		cur = nil
-	}
-
-	for _, r := range recs {
-		key := r.Service + "\x00" + r.Metric
-		thrVal, ok := thr[key]
-		if !ok {
-			if cur != nil && key != curKey {
-				flush()
-			}
+		if cur != nil {
+			cur.BaselineCost = cur.Expected / float64(cur.Hours)
+			windows = append(windows, *cur)
 			cur = nil
-			curKey = key
-			curService, curMetric = r.Service, r.Metric
-			continue
 		}
+	}
 
-		cost, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.Units)
+	for i, b := range recs {
+		if b.Service != r.Service || b.Metric != r.Metric {
+			continue
+		}
+		if cur != nil && (b.Dimension != cur.Dimension || b.Timestamp.Sub(last) != in.bucket) {
+			flush()
+		}
+		if prev != nil && (prev.Dimension != b.Dimension || b.Timestamp.Sub(prev.Timestamp) != in.bucket) {
+			prev = nil
+		}
 
-		if key != curKey {
+		cost := s.Usage.shareCost(b.Service, b.Metric, b.Timestamp, in.pos[i], b.Units)
+		var (
+			expected float64
+			sev      port.Severity
+		)
+		if !b.Timestamp.Before(in.start) {
+			expected, sev = s.expectedCost(r, b, cost, in.pos[i], prev, in)
+		}
+		prev = &recs[i]
+		if sev == "" {
 			flush()
-			curKey = key
-			curService, curMetric = r.Service, r.Metric
-		}
-
-		if cost > thrVal {
-			if cur == nil {
-				cur = &curWin{start: r.Timestamp, end: r.Timestamp.Add(bucket), hours: 1, real: cost, last: r.Timestamp, thr: thrVal}
-			} else if r.Timestamp.Sub(cur.last) == bucket {
-				cur.end = r.Timestamp.Add(bucket)
-				cur.hours++
-				cur.real += cost
-				cur.last = r.Timestamp
-			} else {
-				flush()
-				cur = &curWin{start: r.Timestamp, end: r.Timestamp.Add(bucket), hours: 1, real: cost, last: r.Timestamp, thr: thrVal}
+			continue
+		}
+
+		if cur == nil {
+			cur = &AlertWindow{
+				Service:   b.Service,
+				Metric:    b.Metric,
+				Dimension: b.Dimension,
+				Kind:      r.Kind,
+				Baseline:  baselineLabel(r),
+				Start:     b.Timestamp,
+				Threshold: r.Threshold,
+				Severity:  sev,
+				Rule:      r,
 			}
-		} else if cur != nil {
-			flush()
 		}
+		cur.Severity = port.MaxSeverity(cur.Severity, sev)
+		cur.End = b.Timestamp.Add(in.bucket)
+		cur.Hours++
+		cur.RealCost += cost
+		cur.PeakCost = max(cur.PeakCost, cost)
+		cur.Costs = append(cur.Costs, cost)
+		cur.Expected += expected
+		last = b.Timestamp
 	}
 	flush()
 
-	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.After(windows[j].Start) })
-	return windows, nil
+	return windows
+}
+
+// expectedCost returns the baseline cost of a bucket and the severity of its
+// breach of the rule, empty when the bucket did not breach it. Baseline units
+// are priced like the bucket, at its billing position pos. prev is the
+// previous bucket of the same dimension, if any.
+func (s *AlertService) expectedCost(r port.AlertRule, b port.MetricBucket, cost float64, pos billingPosition, prev *port.MetricBucket, in ruleInputs) (float64, port.Severity) {
+	switch r.Kind {
+	case port.RuleAnomaly:
+		slot := b.Timestamp.Unix() % int64(week.Seconds())
+		base, ok := in.baselines[baselineKey(b.Service, b.Metric, b.Dimension, slot)]
+		if !ok || base.Samples < minBaselineSamples {
+			return 0, ""
+		}
+
+		center, spread := base.Mean, base.StdDev
+		if r.Method == port.MethodMAD {
+			center, spread = base.Median, madScale*base.MAD
+		}
+		expected := s.Usage.shareCost(b.Service, b.Metric, b.Timestamp, pos, center)
+		if spread == 0 {
+			// A perfectly flat baseline: any increase is a surprise.
+			if b.Units > center {
+				return expected, severity(math.Inf(1), r.Sensitivity, r.Critical)
+			}
+			return expected, ""
+		}
+		return expected, severity((b.Units-center)/spread, r.Sensitivity, r.Critical)
+
+	case port.RuleBaseline:
+		p, ok := in.percentiles[r.BaselineDays][b.Service+"\x00"+b.Metric+"\x00"+b.Dimension]
+		if !ok {
+			return 0, ""
+		}
+		units := p.P90
+		switch r.Percentile {
+		case port.P50:
+			units = p.P50
+		case port.P95:
+			units = p.P95
+		case port.PMax:
+			units = p.PMax
+		}
+		expected := s.Usage.shareCost(b.Service, b.Metric, b.Timestamp, pos, units)
+		if expected <= 0 {
+			return expected, ""
+		}
+		return expected, severity(100*(cost/expected-1), r.Percent, r.Critical)
+
+	case port.RuleChange:
+		if prev == nil {
+			return 0, ""
+		}
+		expected := s.Usage.shareCost(b.Service, b.Metric, b.Timestamp, pos, prev.Units)
+		if expected <= 0 {
+			return expected, ""
+		}
+		return expected, severity(100*(cost/expected-1), r.Percent, r.Critical)
+
+	default:
+		return r.Threshold, severity(cost, r.Threshold, r.Critical)
+	}
 }
 
-// SendAlerts computes windows over a lookback and sends notifications using the injected Notifier.
+// severity rates a value against the warning level of a rule and its critical
+// level, if set (non-zero).
+func severity(v, warning, critical float64) port.Severity {
+	switch {
+	case critical > 0 && v > critical:
+		return port.SeverityCritical
+	case v > warning:
+		return port.SeverityWarning
+	}
+	return ""
+}
+
+// baselineLabel describes what a rule compares buckets against.
+func baselineLabel(r port.AlertRule) string {
+	switch r.Kind {
+	case port.RuleAnomaly:
+		stat := "mean"
+		if r.Method == port.MethodMAD {
+			stat = "median"
+		}
+		return fmt.Sprintf("%s of the same hour over %d weeks", stat, r.BaselineWeeks)
+	case port.RuleBaseline:
+		return fmt.Sprintf("%s over %d days", r.Percentile, r.BaselineDays)
+	case port.RuleChange:
+		return "previous hour"
+	default:
+		return "threshold"
+	}
+}
+
+// EvaluateBudgets returns the status of every budget rule for the period
+// containing now. A budget without service or metric covers the spend of all
+// services or metrics.
+func (s *AlertService) EvaluateBudgets(ctx context.Context, now time.Time) ([]BudgetStatus, error) {
+	rules, err := s.Alerts.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rules.List: %w", err)
+	}
+
+	var out []BudgetStatus
+	for _, r := range rules {
+		if r.Kind != port.RuleBudget {
+			continue
+		}
+		if err := ValidateRule(&r); err != nil {
+			return nil, fmt.Errorf("budget %s/%s: %w", r.Service, r.Metric, err)
+		}
+
+		start, end := r.Period.Bounds(now)
+		fcs, err := s.Forecast.Project(ctx, now, start, end, port.MetricsQuery{Service: r.Service, Metric: r.Metric})
+		if err != nil {
+			return nil, fmt.Errorf("forecast.Project: %w", err)
+		}
+		var spend float64
+		for _, f := range fcs {
+			if r.Basis == port.BasisForecast {
+				spend += f.Projected
+			} else {
+				spend += f.Actual
+			}
+		}
+
+		st := BudgetStatus{
+			Rule:        r,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Spend:       roundCents(spend),
+			Percent:     100 * spend / r.Amount,
+		}
+		for _, p := range r.Percentages {
+			if st.Percent >= p && p > st.Crossed {
+				st.Crossed = p
+			}
+		}
+		switch {
+		case r.Critical > 0 && st.Percent >= r.Critical:
+			st.Crossed = max(st.Crossed, r.Critical)
+			st.Severity = port.SeverityCritical
+		case st.Crossed > 0:
+			st.Severity = port.SeverityWarning
+		}
+		out = append(out, st)
+	}
+
+	return out, nil
+}
+
+// SendAlerts computes windows and budgets and sends notifications using the
+// injected Notifier. When Incidents is set, every breached rule is tracked as
+// an incident, and incidents of rules that no longer fire are resolved.
+// Notifications of service/metrics matched by an active silence are held back.
 func (s *AlertService) SendAlerts(ctx context.Context) error {
 	if s.Notify == nil || s.Alerts == nil {
 		return nil // nothing to do if not wired
 	}
-	now := time.Now().UTC()
+	run := &alertRun{now: time.Now().UTC(), seen: make(map[port.IncidentKey]bool)}
+	if s.Silences != nil {
+		sils, err := s.Silences.ListSilences(ctx, run.now, run.now)
+		if err != nil {
+			return fmt.Errorf("silences.List: %w", err)
+		}
+		run.silences = sils
+	}
+	if err := s.sendBudgetAlerts(ctx, run); err != nil {
+		return err
+	}
+	if err := s.sendWindowAlerts(ctx, run); err != nil {
+		return err
+	}
+	s.resolveIncidents(ctx, run)
+	if s.Outbox != nil {
+		s.flushOutbox(ctx, run)
+	}
+	return errors.Join(run.errs...)
+}
+
+// alertRun is the state of a SendAlerts run.
+type alertRun struct {
+	now      time.Time
+	seen     map[port.IncidentKey]bool // rules that fired, and budgets evaluated
+	silences []port.Silence            // active at now
+	errs     []error                   // failed notifications
+}
+
+func (r *alertRun) silenced(service, metric string) bool {
+	for _, sil := range r.silences {
+		if sil.Matches(service, metric) {
+			return true
+		}
+	}
+	return false
+}
+
+// routes returns the channels the alerts of rule r with severity sev go to:
+// the notification channels of the rule, or else the notifier of the
+// severity. Notifications are recorded under the names of the channels, which
+// channelNotifier resolves. Unknown channels of the rule are reported, and the
+// alerts of a rule without any known channel fall back to the notifier of the
+// severity so that they are not lost.
+func (s *AlertService) routes(run *alertRun, r port.AlertRule, sev port.Severity) []string {
+	var out []string
+	for _, name := range r.Channels {
+		if _, ok := s.Channels[name]; !ok {
+			run.errs = append(run.errs, fmt.Errorf("rule %s/%s: unknown notification channel %q", r.Service, r.Metric, name))
+			continue
+		}
+		out = append(out, name)
+	}
+	switch {
+	case len(out) > 0:
+		return out
+	case s.SeverityNotifiers[sev] != nil:
+		return []string{string(sev)}
+	case s.NotifyChannel != "":
+		return []string{s.NotifyChannel}
+	}
+	return []string{notifyChannel}
+}
+
+// sendBudgetAlerts notifies every budget that crossed a higher percentage than
+// already notified for its current period.
+func (s *AlertService) sendBudgetAlerts(ctx context.Context, run *alertRun) error {
+	now := run.now
+	sts, err := s.EvaluateBudgets(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, st := range sts {
+		r := st.Rule
+		run.seen[budgetIncidentKey(r)] = true
+		inc, tracked := s.trackBudget(ctx, now, st)
+		if st.Crossed == 0 || run.silenced(r.Service, r.Metric) {
+			continue
+		}
+		notified, err := s.Alerts.GetBudgetNotified(ctx, r.Service, r.Metric, r.Period, st.PeriodStart)
+		if err != nil || notified >= st.Crossed {
+			continue
+		}
+
+		ev := port.AlertEvent{
+			DedupKey: dedupKey(budgetIncidentKey(r), st.PeriodStart),
+			State:    port.AlertFiring,
+			Severity: st.Severity,
+			Rule:     r,
+			Service:  r.Service,
+			Metric:   r.Metric,
+			Kind:     port.RuleBudget,
+			Start:    st.PeriodStart,
+			End:      st.PeriodEnd,
+			Expected: r.Amount,
+			Actual:   st.Spend,
+			Percent:  st.Percent,
+		}
+		if err := s.notify(ctx, run, &inc, tracked, s.routes(run, r, st.Severity), ev); err != nil {
+			continue
+		}
+		_ = s.Alerts.SetBudgetNotified(ctx, r.Service, r.Metric, r.Period, st.PeriodStart, st.Crossed)
+	}
+	return nil
+}
+
+// sendWindowAlerts computes threshold and anomaly windows over a lookback and notifies recent ones.
+func (s *AlertService) sendWindowAlerts(ctx context.Context, run *alertRun) error {
+	now := run.now
 	start := now.Add(-48 * time.Hour)
 	end := now // use precise now to allow detecting ongoing windows in the current bucket
 	bucket := time.Hour
 
-	wins, err := s.ComputeWindows(ctx, start, end, bucket)
+	wins, err := s.ComputeWindows(ctx, start, end, bucket, port.MetricsQuery{})
 	if err != nil {
 		return err
 	}
@@ -149,28 +764,78 @@ func (s *AlertService) SendAlerts(ctx context.Context) error {
 		if !w.End.After(recentCutoff) {
 			continue
 		}
-		lastUnix, ok, err := s.Alerts.GetLastNotified(ctx, w.Service, w.Metric)
-		if err != nil {
+		run.seen[windowIncidentKey(w)] = true
+		// The current bucket may not have been collected yet: only a window that
+		// ended before the previous bucket resolves its incident.
+		active := w.End.After(lastBucketStart.Add(-bucket))
+		escalated := active && w.Rule.EscalateAfter > 0 && w.Severity != port.SeverityCritical && now.Sub(w.Start) >= w.Rule.EscalateAfter
+		if escalated {
+			w.Severity = port.SeverityCritical
+		}
+		inc, prev, tracked := s.trackWindow(ctx, now, w, active)
+		if tracked && inc.Status == port.IncidentResolved && !inc.NotifiedAt.IsZero() {
+			// A notified window that is over is followed up by a recovery
+			// notification, once.
+			if !inc.ResolvedAt.Equal(prev.ResolvedAt) {
+				s.sendRecovery(ctx, run, &inc, w.Rule)
+			}
 			continue
 		}
-		if ok {
-			last := time.Unix(lastUnix, 0).UTC()
-			if now.Sub(last) < time.Hour {
+		if run.silenced(w.Service, w.Metric) {
+			continue
+		}
+
+		// Open windows are notified again every RenotifyInterval until their
+		// incident is acknowledged, and right away when they turn critical.
+		// Without incidents, the last notification of the rule and dimension
+		// value is used instead.
+		if tracked {
+			raised := prev.Severity == port.SeverityWarning && inc.Severity == port.SeverityCritical
+			if !inc.AckedAt.IsZero() || (!raised && !inc.NotifiedAt.IsZero() && now.Sub(inc.NotifiedAt) < w.Rule.RenotifyInterval) {
 				continue
 			}
-		}
-		expected := w.Threshold * float64(w.Hours)
-		ongoing := w.End.After(lastBucketStart)
-		var text string
-		if ongoing {
-			text = fmt.Sprintf("[CostWatch] Alert: %s/%s exceeded threshold for %dh (expected $%.2f, actual $%.2f) since %s UTC (ongoing)", w.Service, w.Metric, w.Hours, expected, w.RealCost, w.Start.Format(time.RFC3339))
 		} else {
-			text = fmt.Sprintf("[CostWatch] Alert: %s/%s exceeded threshold for %dh (expected $%.2f, actual $%.2f) from %s to %s UTC", w.Service, w.Metric, w.Hours, expected, w.RealCost, w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+			lastUnix, ok, err := s.Alerts.GetLastNotified(ctx, windowIncidentKey(w))
+			if err != nil {
+				continue
+			}
+			if ok {
+				last := time.Unix(lastUnix, 0).UTC()
+				if now.Sub(last) < w.Rule.RenotifyInterval {
+					continue
+				}
+			}
+		}
+		// The start of a long window may fall out of the lookback: the alert
+		// keeps the start of its incident.
+		start := w.Start
+		if tracked {
+			start = inc.StartedAt
+		}
+		ev := port.AlertEvent{
+			DedupKey:  dedupKey(windowIncidentKey(w), start),
+			State:     port.AlertFiring,
+			Severity:  w.Severity,
+			Rule:      w.Rule,
+			Service:   w.Service,
+			Metric:    w.Metric,
+			Dimension: w.Dimension,
+			Kind:      w.Kind,
+			Baseline:  w.Baseline,
+			Start:     w.Start,
+			Hours:     w.Hours,
+			Expected:  w.Expected,
+			Actual:    w.RealCost,
+			Costs:     w.Costs,
+			Escalated: escalated,
+		}
+		if !w.End.After(lastBucketStart) {
+			ev.End = w.End
 		}
-		if err := s.Notify.Send(ctx, text); err != nil {
+		if err := s.notify(ctx, run, &inc, tracked, s.routes(run, w.Rule, w.Severity), ev); err != nil {
 			continue
 		}
-		_ = s.Alerts.SetLastNotified(ctx, w.Service, w.Metric, now.Unix())
+		_ = s.Alerts.SetLastNotified(ctx, windowIncidentKey(w), now.Unix())
 	}
 	return nil
 }
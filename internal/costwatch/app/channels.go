@@ -0,0 +1,105 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// channelName is the format of the names of notification channels, which
+// rules store as a comma separated list.
+var channelName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// reservedChannels are the names notifications are recorded under when they
+// do not go to a notification channel: the notifiers of ALERT_NOTIFIER and of
+// the severities.
+var reservedChannels = []string{notifyChannel, "pagerduty", "email", string(port.SeverityWarning), string(port.SeverityCritical)}
+
+// IsDefaultChannel reports whether name is one of the reserved names of the
+// channels configured in the env.
+func IsDefaultChannel(name string) bool {
+	return slices.Contains(reservedChannels, name)
+}
+
+// ValidateChannelName checks the name of a notification channel: lowercase
+// letters, digits, dashes and underscores, other than the reserved names.
+func ValidateChannelName(name string) error {
+	if !channelName.MatchString(name) {
+		return fmt.Errorf("channel names must be 1 to 63 lowercase letters, digits, dashes or underscores, got %q", name)
+	}
+	if slices.Contains(reservedChannels, name) {
+		return fmt.Errorf("channel name %q is reserved", name)
+	}
+	return nil
+}
+
+// NotificationTest is the outcome of a test notification. StatusCode and
+// Response are the status and start of the body of an endpoint that answered
+// with a non-2xx status.
+type NotificationTest struct {
+	Channel    string
+	Delivered  bool
+	StatusCode int
+	Response   string
+	Error      string
+}
+
+// testRule is the rule of test notifications.
+var testRule = port.AlertRule{Service: "costwatch", Metric: "test_notification", Kind: port.RuleThreshold, Threshold: 1}
+
+// TestNotification sends a sample alert of sev (default warning) and state
+// (default firing) to channel, or to the default channel of sev if empty, and reports whether it was delivered. The alert is
+// rendered and sent by the same notifiers as those of SendAlerts, but right
+// away: it bypasses the outbox, silences and incidents. A resolved test
+// notification closes the alert of the previous ones in alert trackers such
+// as PagerDuty.
+func (s *AlertService) TestNotification(ctx context.Context, channel string, sev port.Severity, state port.AlertState) (NotificationTest, error) {
+	sev = cmp.Or(sev, port.SeverityWarning)
+	state = cmp.Or(state, port.AlertFiring)
+	if channel == "" {
+		channel = s.routes(&alertRun{}, testRule, sev)[0]
+	}
+	n, err := s.channelNotifier(channel)
+	if err != nil {
+		return NotificationTest{}, err
+	}
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	ev := port.AlertEvent{
+		DedupKey: "costwatch:test:" + channel,
+		State:    state,
+		Severity: sev,
+		Rule:     testRule,
+		Service:  testRule.Service,
+		Metric:   testRule.Metric,
+		Kind:     testRule.Kind,
+		Start:    end.Add(-3 * time.Hour),
+		Hours:    3,
+		Expected: 3,
+		Actual:   5.8,
+		Costs:    []float64{1.4, 1.9, 2.5},
+	}
+	if ev.Resolved() {
+		ev.End = end
+		ev.Overspend = 2.8
+		ev.PeakCost = 2.5
+	}
+
+	res := NotificationTest{Channel: channel, Delivered: true}
+	if err := n.Send(ctx, ev); err != nil {
+		res.Delivered = false
+		res.Error = err.Error()
+		var re *port.ResponseError
+		if errors.As(err, &re) {
+			res.StatusCode = re.StatusCode
+			res.Response = re.Body
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// forecastHistory is the minimum number of past days fitted by Project.
+	forecastHistory = 28 * day
+
+	// forecastZ is the z-score of the forecast band (a 90% interval).
+	forecastZ = 1.645
+	// seasonalMinDays is the number of complete days needed before day-of-week
+	// seasonality is estimated; with fewer, every weekday weighs the same.
+	seasonalMinDays = 14
+)
+
+// Forecast is the projected spend of a service/metric for a period. Actual is
+// the spend of the period so far, and Lower and Upper bound Projected with a
+// 90% confidence band.
+type Forecast struct {
+	Service   string
+	Metric    string
+	Actual    float64
+	Projected float64
+	Lower     float64
+	Upper     float64
+}
+
+// ForecastService projects period-end spend from costed daily usage.
+type ForecastService struct {
+	Usage *UsageService
+}
+
+func NewForecastService(usage *UsageService) *ForecastService {
+	return &ForecastService{Usage: usage}
+}
+
+// MonthEnd projects the spend of the billing month containing now, per service/metric.
+func (s *ForecastService) MonthEnd(ctx context.Context, now time.Time, q port.MetricsQuery) ([]Forecast, error) {
+	start := billingMonth(now)
+	return s.Project(ctx, now, start, start.AddDate(0, 1, 0), q)
+}
+
+// Project projects the spend of the UTC-day-aligned period [start, end), which
+// contains now, per service/metric.
+//
+// The complete days of the period, or of the last 4 weeks when that is longer,
+// are fitted with a linear trend after removing day-of-week seasonality, and the
+// fit is extrapolated over the rest of the period (including what remains of
+// today). The band widens with the residual spread of the fit and the number of
+// days left.
+func (s *ForecastService) Project(ctx context.Context, now, start, end time.Time, q port.MetricsQuery) ([]Forecast, error) {
+	now = now.UTC()
+	todayStart := now.Truncate(day)
+	histStart := start
+	if h := todayStart.Add(-forecastHistory); h.Before(histStart) {
+		histStart = h
+	}
+
+	q.GroupBy = ""
+	items, err := s.Usage.Usage(ctx, histStart, now, day, q)
+	if err != nil {
+		return nil, fmt.Errorf("usage.Usage: %w", err)
+	}
+
+	type series struct {
+		service, metric string
+		first           int
+		daily           map[int]float64 // days since histStart -> cost
+	}
+	byKey := make(map[string]*series)
+	for _, it := range items {
+		key := it.Service + "\x00" + it.Metric
+		d := int(it.Timestamp.Sub(histStart) / day)
+		sr, ok := byKey[key]
+		if !ok {
+			sr = &series{service: it.Service, metric: it.Metric, first: d, daily: make(map[int]float64)}
+			byKey[key] = sr
+		}
+		sr.first = min(sr.first, d)
+		sr.daily[d] += it.Cost
+	}
+
+	first := int(start.Sub(histStart) / day)
+	today := int(todayStart.Sub(histStart) / day)
+	last := int(end.Sub(histStart) / day)
+	todayLeft := 1 - float64(now.Sub(todayStart))/float64(day)
+
+	out := make([]Forecast, 0, len(byKey))
+	for _, sr := range byKey {
+		var actual float64
+		for d, c := range sr.daily {
+			if d >= first {
+				actual += c
+			}
+		}
+
+		// Complete days only, from the first with data: today's partial cost, and
+		// the days before the metric existed, would drag the trend down.
+		ys := make([]float64, max(0, today-sr.first))
+		for i := range ys {
+			ys[i] = sr.daily[sr.first+i]
+		}
+		if len(ys) == 0 && todayLeft < 1 {
+			// Without history, extrapolate the rate seen so far today.
+			ys = []float64{sr.daily[today] / (1 - todayLeft)}
+			sr.first = today
+		}
+		m := fitDaily(ys, histStart.AddDate(0, 0, sr.first))
+
+		var projected, variance float64
+		for d := today; d < last; d++ {
+			w := 1.0
+			if d == today {
+				w = todayLeft
+			}
+			wd := histStart.AddDate(0, 0, d).Weekday()
+			projected += w * m.predict(d-sr.first, wd)
+			variance += math.Pow(w*m.sigma*m.season[wd], 2)
+		}
+
+		spread := forecastZ * math.Sqrt(variance)
+		total := actual + projected
+		out = append(out, Forecast{
+			Service:   sr.service,
+			Metric:    sr.metric,
+			Actual:    roundCents(actual),
+			Projected: roundCents(total),
+			Lower:     roundCents(math.Max(actual, total-spread)),
+			Upper:     roundCents(total + spread),
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Service != out[j].Service {
+			return out[i].Service < out[j].Service
+		}
+		return out[i].Metric < out[j].Metric
+	})
+	return out, nil
+}
+
+// dailyModel is a linear trend of deseasonalized daily cost.
+type dailyModel struct {
+	intercept, slope float64
+	sigma            float64 // residual standard deviation of the deseasonalized cost
+	season           [7]float64
+}
+
+func (m dailyModel) predict(d int, wd time.Weekday) float64 {
+	return math.Max(0, (m.intercept+m.slope*float64(d))*m.season[wd])
+}
+
+// fitDaily fits ys, the costs of consecutive days starting at first, with
+// ordinary least squares after dividing out day-of-week factors.
+func fitDaily(ys []float64, first time.Time) dailyModel {
+	var m dailyModel
+	for i := range m.season {
+		m.season[i] = 1
+	}
+	n := len(ys)
+	if n == 0 {
+		return m
+	}
+
+	var mean float64
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(n)
+
+	if n >= seasonalMinDays && mean > 0 {
+		var sums, counts [7]float64
+		for d, y := range ys {
+			wd := first.AddDate(0, 0, d).Weekday()
+			sums[wd] += y
+			counts[wd]++
+		}
+		for wd := range m.season {
+			if counts[wd] > 0 && sums[wd] > 0 {
+				m.season[wd] = sums[wd] / counts[wd] / mean
+			}
+		}
+	}
+
+	xs := make([]float64, n)
+	adj := make([]float64, n)
+	var mx, my float64
+	for d, y := range ys {
+		xs[d] = float64(d)
+		adj[d] = y / m.season[first.AddDate(0, 0, d).Weekday()]
+		mx += xs[d]
+		my += adj[d]
+	}
+	mx /= float64(n)
+	my /= float64(n)
+
+	var sxy, sxx float64
+	for d := range xs {
+		sxy += (xs[d] - mx) * (adj[d] - my)
+		sxx += (xs[d] - mx) * (xs[d] - mx)
+	}
+	if sxx > 0 {
+		m.slope = sxy / sxx
+	}
+	m.intercept = my - m.slope*mx
+
+	if n > 2 {
+		var sse float64
+		for d := range xs {
+			r := adj[d] - (m.intercept + m.slope*xs[d])
+			sse += r * r
+		}
+		m.sigma = math.Sqrt(sse / float64(n-2))
+	} else {
+		// Too few days to measure the spread: assume it is as large as the level.
+		m.sigma = my
+	}
+
+	return m
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// notifyChannel is the channel notifications sent to AlertService.Notify are
+// recorded under by default.
+const notifyChannel = "webhook"
+
+func windowIncidentKey(w AlertWindow) port.IncidentKey {
+	return port.IncidentKey{Service: w.Service, Metric: w.Metric, Kind: w.Kind, Dimension: w.Dimension}
+}
+
+func budgetIncidentKey(r port.AlertRule) port.IncidentKey {
+	return port.IncidentKey{Service: r.Service, Metric: r.Metric, Kind: port.RuleBudget, Period: r.Period}
+}
+
+// trackWindow opens or updates the incident of a window, and resolves it at the
+// end of the window once the window is no longer active. prev is the incident
+// as it was before this call, zero for a new incident. ok is false when
+// incidents are not recorded, or when the window predates the latest incident
+// of its rule.
+func (s *AlertService) trackWindow(ctx context.Context, now time.Time, w AlertWindow, active bool) (inc, prev port.Incident, ok bool) {
+	if s.Incidents == nil {
+		return port.Incident{}, port.Incident{}, false
+	}
+	key := windowIncidentKey(w)
+	inc, found, err := s.Incidents.Latest(ctx, key)
+	if err != nil {
+		return port.Incident{}, port.Incident{}, false
+	}
+
+	switch {
+	case found && w.Start.Before(inc.StartedAt):
+		return port.Incident{}, port.Incident{}, false
+	case found && inc.Status == port.IncidentResolved && !w.End.After(inc.ResolvedAt):
+		return inc, inc, true // already recorded
+	case found && (inc.Status == port.IncidentOpen || w.Start.Before(inc.ResolvedAt)):
+		// The same window, possibly continued after it was resolved.
+		prev = inc
+	default:
+		inc = port.Incident{IncidentKey: key, StartedAt: w.Start}
+	}
+
+	inc.Status = port.IncidentOpen
+	inc.ResolvedAt = time.Time{}
+	inc.UpdatedAt = now
+	inc.PeakCost = max(inc.PeakCost, w.PeakCost)
+	inc.Overspend = max(inc.Overspend, roundCents(w.RealCost-w.Expected))
+	inc.Severity = port.MaxSeverity(inc.Severity, w.Severity)
+	if !active {
+		inc.Status = port.IncidentResolved
+		inc.ResolvedAt = w.End
+	}
+	if err := s.saveIncident(ctx, &inc); err != nil {
+		return port.Incident{}, port.Incident{}, false
+	}
+	return inc, prev, true
+}
+
+// trackBudget resolves the incident of a budget at the end of its period, and
+// opens or updates the incident of the current period once a percentage was
+// crossed. It reports false when there is no incident for the current period.
+func (s *AlertService) trackBudget(ctx context.Context, now time.Time, st BudgetStatus) (port.Incident, bool) {
+	if s.Incidents == nil {
+		return port.Incident{}, false
+	}
+	inc, found, err := s.Incidents.Latest(ctx, budgetIncidentKey(st.Rule))
+	if err != nil {
+		return port.Incident{}, false
+	}
+
+	if found && inc.Status == port.IncidentOpen && inc.StartedAt.Before(st.PeriodStart) {
+		inc.Status = port.IncidentResolved
+		inc.ResolvedAt = st.PeriodStart
+		inc.UpdatedAt = now
+		if err := s.saveIncident(ctx, &inc); err != nil {
+			return port.Incident{}, false
+		}
+	}
+	if st.Crossed == 0 {
+		return port.Incident{}, false
+	}
+	if !found || inc.Status != port.IncidentOpen {
+		inc = port.Incident{IncidentKey: budgetIncidentKey(st.Rule), Status: port.IncidentOpen, StartedAt: now}
+	}
+
+	inc.UpdatedAt = now
+	inc.PeakCost = max(inc.PeakCost, st.Spend)
+	inc.Overspend = max(inc.Overspend, roundCents(st.Spend-st.Rule.Amount))
+	inc.Severity = port.MaxSeverity(inc.Severity, st.Severity)
+	if err := s.saveIncident(ctx, &inc); err != nil {
+		return port.Incident{}, false
+	}
+	return inc, true
+}
+
+// resolveIncidents resolves the open incidents of rules that were not seen
+// firing (or, for budgets, evaluated) by the last SendAlerts run, and sends
+// recovery notifications for the windows among them.
+func (s *AlertService) resolveIncidents(ctx context.Context, run *alertRun) {
+	if s.Incidents == nil {
+		return
+	}
+	open, err := s.Incidents.ListOpen(ctx)
+	if err != nil {
+		return
+	}
+	var rules []port.AlertRule
+	if len(open) > 0 {
+		rules, _ = s.Alerts.ListRules(ctx)
+	}
+	for _, inc := range open {
+		if run.seen[inc.IncidentKey] {
+			continue
+		}
+		inc.Status = port.IncidentResolved
+		inc.ResolvedAt = run.now
+		inc.UpdatedAt = run.now
+		if err := s.saveIncident(ctx, &inc); err != nil {
+			continue
+		}
+		if inc.Kind != port.RuleBudget {
+			s.sendRecovery(ctx, run, &inc, incidentRule(rules, inc.IncidentKey))
+		}
+	}
+}
+
+// sendRecovery tells that the window of a resolved incident is over, if the
+// incident was notified while it was open. It goes to the notifier of the
+// highest severity of the incident, or the channels of its rule. Silences hold
+// recoveries back, except from alert trackers. rule is the rule of the incident.
+func (s *AlertService) sendRecovery(ctx context.Context, run *alertRun, inc *port.Incident, rule port.AlertRule) {
+	if inc.NotifiedAt.IsZero() {
+		return
+	}
+	channels := s.routes(run, rule, inc.Severity)
+	if run.silenced(inc.Service, inc.Metric) {
+		channels = slices.DeleteFunc(channels, func(c string) bool {
+			n, _ := s.channelNotifier(c)
+			t, ok := n.(port.AlertTracker)
+			return !(ok && t.TracksAlerts())
+		})
+	}
+	if len(channels) == 0 {
+		return
+	}
+	ev := port.AlertEvent{
+		DedupKey:  dedupKey(inc.IncidentKey, inc.StartedAt),
+		State:     port.AlertResolved,
+		Severity:  inc.Severity,
+		Rule:      rule,
+		Service:   inc.Service,
+		Metric:    inc.Metric,
+		Dimension: inc.Dimension,
+		Kind:      inc.Kind,
+		Start:     inc.StartedAt,
+		End:       inc.ResolvedAt,
+		Overspend: inc.Overspend,
+		PeakCost:  inc.PeakCost,
+	}
+	_ = s.notify(ctx, run, inc, true, channels, ev)
+}
+
+// incidentRule returns the rule an incident belongs to, or a rule with the
+// key of the incident if it was deleted.
+func incidentRule(rules []port.AlertRule, k port.IncidentKey) port.AlertRule {
+	for _, r := range rules {
+		kind := r.Kind
+		if kind == "" {
+			kind = port.RuleThreshold
+		}
+		if r.Service == k.Service && r.Metric == k.Metric && kind == k.Kind && r.Period == k.Period {
+			return r
+		}
+	}
+	return port.AlertRule{Service: k.Service, Metric: k.Metric, Kind: k.Kind, Period: k.Period}
+}
+
+// dedupKey identifies the notifications of the alert of a rule that started at
+// start, the start of a window or of a budget period.
+func dedupKey(k port.IncidentKey, start time.Time) string {
+	return strings.Join([]string{"costwatch", string(k.Kind), k.Service, k.Metric, string(k.Period), k.Dimension, strconv.FormatInt(start.Unix(), 10)}, ":")
+}
+
+func (s *AlertService) saveIncident(ctx context.Context, inc *port.Incident) error {
+	if inc.ID != 0 {
+		return s.Incidents.UpdateIncident(ctx, *inc)
+	}
+	id, err := s.Incidents.CreateIncident(ctx, *inc)
+	if err != nil {
+		return err
+	}
+	inc.ID = id
+	return nil
+}
+
+// recordNotification records a notification attempt for an incident on a
+// channel, along with the error of a failed delivery.
+func (s *AlertService) recordNotification(ctx context.Context, now time.Time, inc *port.Incident, channel, text string, sendErr error) {
+	n := port.Notification{IncidentID: inc.ID, SentAt: now, Channel: channel, Message: text}
+	if sendErr != nil {
+		n.Error = sendErr.Error()
+	} else {
+		inc.NotifiedAt = now
+	}
+	if err := s.Incidents.AddNotification(ctx, n); err != nil {
+		return
+	}
+	inc.Summary = text
+	inc.UpdatedAt = now
+	_ = s.Incidents.UpdateIncident(ctx, *inc)
+}
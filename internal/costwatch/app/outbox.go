@@ -0,0 +1,137 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// A failed delivery is attempted again after deliveryBackoff, doubling after
+// every failure up to maxDeliveryBackoff, and fails for good after
+// maxDeliveryAttempts attempts. Deliveries are attempted by SendAlerts, so
+// retries happen on the first run after their backoff.
+const (
+	deliveryBackoff     = time.Minute
+	maxDeliveryBackoff  = 6 * time.Hour
+	maxDeliveryAttempts = 10
+)
+
+// retryBackoff returns how long to wait after the given number of failed
+// attempts.
+func retryBackoff(attempts int) time.Duration {
+	d := deliveryBackoff
+	for i := 1; i < attempts && d < maxDeliveryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxDeliveryBackoff)
+}
+
+// notify delivers an alert to channels. With an outbox, the alert is only
+// enqueued, and delivered by flushOutbox at the end of the run: a nil error
+// means the alert will be delivered, or retried until it fails for good.
+// Without, it is sent right away. It fails if any channel failed. inc is the
+// incident of the alert if tracked.
+func (s *AlertService) notify(ctx context.Context, run *alertRun, inc *port.Incident, tracked bool, channels []string, ev port.AlertEvent) error {
+	var errs []error
+	for _, channel := range channels {
+		if s.Outbox == nil {
+			n, err := s.channelNotifier(channel)
+			if err == nil {
+				err = n.Send(ctx, ev)
+			}
+			if tracked {
+				s.recordNotification(ctx, run.now, inc, channel, ev.Summary(), err)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("notify %s: %w", channel, err))
+			}
+			continue
+		}
+
+		d := port.Delivery{Channel: channel, Event: ev, CreatedAt: run.now, NextAttemptAt: run.now}
+		if tracked {
+			d.IncidentID = inc.ID
+		}
+		if _, err := s.Outbox.Enqueue(ctx, d); err != nil {
+			errs = append(errs, fmt.Errorf("outbox.Enqueue: %w", err))
+		}
+	}
+	run.errs = append(run.errs, errs...)
+	return errors.Join(errs...)
+}
+
+// flushOutbox attempts the deliveries that are due, oldest first. Each attempt
+// is recorded on the incident of its alert, which is only notified once a
+// delivery succeeded. A delivery to a channel that was deleted, or is not
+// configured, fails right away.
+func (s *AlertService) flushOutbox(ctx context.Context, run *alertRun) {
+	ds, err := s.Outbox.ListDue(ctx, run.now)
+	if err != nil {
+		run.errs = append(run.errs, fmt.Errorf("outbox.ListDue: %w", err))
+		return
+	}
+	for _, d := range ds {
+		n, sendErr := s.channelNotifier(d.Channel)
+		if sendErr == nil {
+			sendErr = n.Send(ctx, d.Event)
+		}
+		d.Attempts++
+		switch {
+		case sendErr == nil:
+			d.Status = port.DeliveryDelivered
+			d.LastError = ""
+			d.NextAttemptAt = time.Time{}
+			d.DeliveredAt = run.now
+		case n == nil:
+			d.Status = port.DeliveryFailed
+			d.LastError = sendErr.Error()
+			d.NextAttemptAt = time.Time{}
+			run.errs = append(run.errs, fmt.Errorf("delivery %d to %s failed: %w", d.ID, d.Channel, sendErr))
+		case d.Attempts >= maxDeliveryAttempts:
+			d.Status = port.DeliveryFailed
+			d.LastError = sendErr.Error()
+			d.NextAttemptAt = time.Time{}
+			run.errs = append(run.errs, fmt.Errorf("delivery %d to %s failed after %d attempts: %w", d.ID, d.Channel, d.Attempts, sendErr))
+		default:
+			d.LastError = sendErr.Error()
+			d.NextAttemptAt = run.now.Add(retryBackoff(d.Attempts))
+			run.errs = append(run.errs, fmt.Errorf("delivery %d to %s failed, retrying at %s: %w", d.ID, d.Channel, d.NextAttemptAt.Format(time.RFC3339), sendErr))
+		}
+		if err := s.Outbox.UpdateDelivery(ctx, d); err != nil {
+			run.errs = append(run.errs, fmt.Errorf("outbox.Update: %w", err))
+		}
+
+		if d.IncidentID == 0 || s.Incidents == nil {
+			continue
+		}
+		inc, ok, err := s.Incidents.GetIncident(ctx, d.IncidentID)
+		if err != nil || !ok {
+			continue
+		}
+		s.recordNotification(ctx, run.now, &inc, d.Channel, d.Event.Summary(), sendErr)
+	}
+}
+
+// channelNotifier returns the notifier of a channel named by routes. It fails
+// if the channel no longer exists or is not configured, as its notifier would
+// drop the alert.
+func (s *AlertService) channelNotifier(channel string) (port.Notifier, error) {
+	n, ok := s.Channels[channel]
+	if !ok {
+		n, ok = s.SeverityNotifiers[port.Severity(channel)]
+	}
+	if !ok && channel == cmp.Or(s.NotifyChannel, notifyChannel) {
+		n, ok = s.Notify, s.Notify != nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown notification channel %q", channel)
+	}
+	if c, ok := n.(port.ConfigurableNotifier); ok && !c.Configured() {
+		return nil, fmt.Errorf("notification channel %q is not configured", channel)
+	}
+	return n, nil
+}
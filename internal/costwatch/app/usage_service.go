@@ -3,6 +3,8 @@ package app
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/tailbits/costwatch/internal/costwatch/port"
@@ -12,6 +14,7 @@ import (
 type UsageItem struct {
 	Service   string
 	Metric    string
+	Dimension string
 	Timestamp time.Time
 	Cost      float64
 }
@@ -39,38 +42,171 @@ func NewUsageService(metrics port.MetricsRepo, catalog port.Catalog) *UsageServi
 }
 
 // Usage aggregates units per bucket and converts them to cost via the catalog.
-func (s *UsageService) Usage(ctx context.Context, start, end time.Time, bucket time.Duration) ([]UsageItem, error) {
-	recs, err := s.Metrics.Aggregate(ctx, start, end, bucket)
+// When q.GroupBy is set, each bucket is further broken down by that dimension's value.
+// Each bucket is priced at the rate effective at its timestamp, at the margin of the
+// units already consumed in its billing month, so price changes, tiered prices and
+// free allowances are attributed correctly. Filtered and grouped buckets get their
+// share of the cost of their service/metric's bucket.
+func (s *UsageService) Usage(ctx context.Context, start, end time.Time, bucket time.Duration, q port.MetricsQuery) ([]UsageItem, error) {
+	recs, err := s.Metrics.Aggregate(ctx, start, end, bucket, q)
 	if err != nil {
 		return nil, fmt.Errorf("metrics.Aggregate: %w", err)
 	}
 
+	costs, err := s.marginalCosts(ctx, start, end, bucket, recs, q)
+	if err != nil {
+		return nil, err
+	}
+
 	rsp := make([]UsageItem, 0, len(recs))
-	for _, r := range recs {
-		cost, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.Units)
+	for i, r := range recs {
 		rsp = append(rsp, UsageItem{
 			Service:   r.Service,
 			Metric:    r.Metric,
+			Dimension: r.Dimension,
 			Timestamp: r.Timestamp,
-			Cost:      cost,
+			Cost:      costs[i],
 		})
 	}
 
 	return rsp, nil
 }
 
-// UsagePercentiles computes unit percentiles and converts them to cost via the catalog.
-func (s *UsageService) UsagePercentiles(ctx context.Context, start, end time.Time, bucket time.Duration) ([]PercentileCost, error) {
-	recs, err := s.Metrics.Percentiles(ctx, start, end, bucket)
+// marginalCosts prices every bucket at the margin of the units already used
+// in its billing month (see billingPositions).
+func (s *UsageService) marginalCosts(ctx context.Context, start, end time.Time, bucket time.Duration, recs []port.MetricBucket, q port.MetricsQuery) ([]float64, error) {
+	pos, err := s.billingPositions(ctx, start, end, bucket, recs, q)
+	if err != nil {
+		return nil, err
+	}
+	costs := make([]float64, len(recs))
+	for i, r := range recs {
+		costs[i] = s.shareCost(r.Service, r.Metric, r.Timestamp, pos[i], r.Units)
+	}
+	return costs, nil
+}
+
+// billingPosition places a bucket in the billing month of its service/metric:
+// mtd is the units the whole service/metric used earlier in the month, and
+// units those it used during the bucket.
+type billingPosition struct {
+	mtd   float64
+	units float64
+}
+
+// billingPositions returns the billing position of every bucket. Tiers and free
+// allowances apply to the whole service/metric, whatever the filters and
+// grouping of q, so the buckets of a filtered or grouped query are placed
+// among those of their service/metric.
+func (s *UsageService) billingPositions(ctx context.Context, start, end time.Time, bucket time.Duration, recs []port.MetricBucket, q port.MetricsQuery) ([]billingPosition, error) {
+	scope := port.MetricsQuery{Service: q.Service, Metric: q.Metric}
+	totals := recs
+	if q != scope {
+		var err error
+		if totals, err = s.Metrics.Aggregate(ctx, start, end, bucket, scope); err != nil {
+			return nil, fmt.Errorf("metrics.Aggregate: %w", err)
+		}
+	}
+	mtd, err := s.monthToDate(ctx, start, totals, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	key := func(b port.MetricBucket) string {
+		return b.Service + "\x00" + b.Metric + "\x00" + strconv.FormatInt(b.Timestamp.Unix(), 10)
+	}
+	byKey := make(map[string]billingPosition, len(totals))
+	for i, t := range totals {
+		byKey[key(t)] = billingPosition{mtd: mtd[i], units: t.Units}
+	}
+	out := make([]billingPosition, len(recs))
+	for i, r := range recs {
+		p, ok := byKey[key(r)]
+		if !ok {
+			p = billingPosition{units: r.Units}
+		}
+		out[i] = p
+	}
+	return out, nil
+}
+
+// shareCost prices units of a bucket at p as their share of the marginal cost
+// of the whole bucket, so the costs of its groups add up to it. Units of an
+// empty bucket are priced at the margin of p.
+func (s *UsageService) shareCost(service, metric string, at time.Time, p billingPosition, units float64) float64 {
+	if p.units <= 0 {
+		cost, _ := s.Catalog.ComputeMarginalCost(service, metric, at, p.mtd, units)
+		return cost
+	}
+	cost, _ := s.Catalog.ComputeMarginalCost(service, metric, at, p.mtd, p.units)
+	return cost * units / p.units
+}
+
+// monthToDate returns, for every bucket, the units of its service/metric
+// consumed earlier in its billing month, seeded from storage for the billing
+// month containing start. recs must not be grouped by a dimension.
+func (s *UsageService) monthToDate(ctx context.Context, start time.Time, recs []port.MetricBucket, q port.MetricsQuery) ([]float64, error) {
+	monthStart := billingMonth(start)
+	mtd := make(map[string]float64)
+	if start.After(monthStart) {
+		totals, err := s.Metrics.Totals(ctx, monthStart, start, q)
+		if err != nil {
+			return nil, fmt.Errorf("metrics.Totals: %w", err)
+		}
+		for _, t := range totals {
+			mtd[t.Service+"\x00"+t.Metric] = t.Units
+		}
+	}
+
+	order := make([]int, len(recs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return recs[order[i]].Timestamp.Before(recs[order[j]].Timestamp)
+	})
+
+	months := make(map[string]time.Time)
+	out := make([]float64, len(recs))
+	for _, i := range order {
+		r := recs[i]
+		key := r.Service + "\x00" + r.Metric
+		m := billingMonth(r.Timestamp)
+		cur, ok := months[key]
+		if !ok {
+			cur = monthStart
+		}
+		if !cur.Equal(m) {
+			mtd[key] = 0
+		}
+		months[key] = m
+
+		out[i] = mtd[key]
+		mtd[key] += r.Units
+	}
+
+	return out, nil
+}
+
+// billingMonth returns the start of the (UTC) billing month containing t.
+func billingMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// UsagePercentiles computes unit percentiles and converts them to cost via the catalog,
+// using the prices effective at the end of the range.
+func (s *UsageService) UsagePercentiles(ctx context.Context, start, end time.Time, bucket time.Duration, q port.MetricsQuery) ([]PercentileCost, error) {
+	recs, err := s.Metrics.Percentiles(ctx, start, end, bucket, q)
 	if err != nil {
 		return nil, err
 	}
 	out := make([]PercentileCost, 0, len(recs))
 	for _, r := range recs {
-		c50, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.P50)
-		c90, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.P90)
-		c95, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.P95)
-		cmax, _ := s.Catalog.ComputeCost(r.Service, r.Metric, r.PMax)
+		c50, _ := s.Catalog.ComputeCost(r.Service, r.Metric, end, r.P50)
+		c90, _ := s.Catalog.ComputeCost(r.Service, r.Metric, end, r.P90)
+		c95, _ := s.Catalog.ComputeCost(r.Service, r.Metric, end, r.P95)
+		cmax, _ := s.Catalog.ComputeCost(r.Service, r.Metric, end, r.PMax)
 
 		out = append(out, PercentileCost{
 			Service: r.Service,
@@ -38,25 +38,20 @@ func (s *ServiceUsage) Cost() float64 {
 }
 
 type CostWatch struct {
-	log *slog.Logger
-	cs  *clickstore.Client
-	db  *sqlstore.Store
+	log     *slog.Logger
+	cs      *clickstore.Client
+	db      *sqlstore.Store
+	catalog port.Catalog
 }
 
-func New(ctx context.Context, log *slog.Logger, cs *clickstore.Client) (*CostWatch, error) {
+func New(ctx context.Context, log *slog.Logger, cs *clickstore.Client, catalog port.Catalog) (*CostWatch, error) {
 	return &CostWatch{
-		log: log,
-		cs:  cs,
+		log:     log,
+		cs:      cs,
+		catalog: catalog,
 	}, nil
 }
 
-// registryCatalog implements MetricCatalog using the global registry in this package.
-type registryCatalog struct{}
-
-func (registryCatalog) ComputeCost(service, metric string, units float64) (float64, bool) {
-	return ComputeCost(service, metric, units)
-}
-
 // getSyncStore returns a lazily-initialized sync-state store.
 func (cw *CostWatch) getSyncStore() (*sqlstore.Store, error) {
 	if cw.db != nil {
@@ -77,22 +72,54 @@ func (cw *CostWatch) FetchMetricForService(ctx context.Context, svc Service, m M
 	if svc == nil || m == nil {
 		return fmt.Errorf("nil service or metric")
 	}
-	batch, err := cw.cs.PrepareBatch(ctx, "insert into metrics (service, metric, value, timestamp)")
-	if err != nil {
-		return fmt.Errorf("prepare batch: %w", err)
-	}
 	dps, err := m.Datapoints(ctx, m.Label(), start, end)
 	if err != nil {
 		return fmt.Errorf("m.Datapoints: %w", err)
 	}
-	for _, dp := range dps {
-		if err := batch.Append(
-			svc.Label(),
-			m.Label(),
-			dp.Value,
-			dp.Timestamp,
-		); err != nil {
-			return fmt.Errorf("batch append: %w", err)
+	return cw.insertDatapoints(ctx, svc.Label(), map[string][]Datapoint{m.Label(): dps})
+}
+
+// FetchMetricsForService fetches several metrics of a BatchService over the same window at once.
+// The datapoints of a partial failure are stored, and the error logged, so that a failing
+// source does not hold back the others.
+func (cw *CostWatch) FetchMetricsForService(ctx context.Context, svc BatchService, ms []Metric, start time.Time, end time.Time) error {
+	if svc == nil {
+		return fmt.Errorf("nil service")
+	}
+	dps, err := svc.BatchDatapoints(ctx, ms, start, end)
+	if err != nil && dps == nil {
+		return fmt.Errorf("svc.BatchDatapoints: %w", err)
+	}
+	if err != nil {
+		cw.log.Error("svc.BatchDatapoints failed in part", "service", svc.Label(), "error", err)
+	}
+	return cw.insertDatapoints(ctx, svc.Label(), dps)
+}
+
+// insertDatapoints stores datapoints keyed by metric label for the given service.
+func (cw *CostWatch) insertDatapoints(ctx context.Context, service string, byMetric map[string][]Datapoint) error {
+	batch, err := cw.cs.PrepareBatch(ctx, "insert into metrics (service, metric, value, timestamp, dimensions, dimensions_key, account, region)")
+	if err != nil {
+		return fmt.Errorf("prepare batch: %w", err)
+	}
+	for metric, dps := range byMetric {
+		for _, dp := range dps {
+			dims := dp.Dimensions
+			if dims == nil {
+				dims = map[string]string{}
+			}
+			if err := batch.Append(
+				service,
+				metric,
+				dp.Value,
+				dp.Timestamp,
+				dims,
+				dp.DimensionsKey(),
+				dp.Account,
+				dp.Region,
+			); err != nil {
+				return fmt.Errorf("batch append: %w", err)
+			}
 		}
 	}
 	if err := batch.Send(); err != nil {
@@ -112,12 +139,34 @@ func (cw *CostWatch) sendAlerts(ctx context.Context) error {
 	m := chinfra.NewMetricsRepo(cw.cs)
 	var a port.AlertsRepo
 	if os.Getenv("ALERT_RULES") != "" {
-		a = envinfra.NewAlertsRepos()
+		// Notification state is kept in SQLite, like incidents and silences.
+		a = envinfra.NewAlertsRepos(sqlinfra.NewAlertsRepos(st))
 	} else {
 		a = sqlinfra.NewAlertsRepos(st)
 	}
-	n := notinfr.NewWebhookNotifierFromEnv()
-	c := registryCatalog{}
-	alerts := appsvc.NewAlertService(m, a, n, c)
+	tmpl, err := notinfr.TemplatesFromEnv()
+	if err != nil {
+		return err
+	}
+	n, channel, err := notinfr.NewFromEnv(tmpl)
+	if err != nil {
+		return err
+	}
+	alerts := appsvc.NewAlertService(m, a, n, cw.catalog)
+	alerts.NotifyChannel = channel
+	alerts.SeverityNotifiers = notinfr.NewSeverityNotifiersFromEnv(tmpl)
+	alerts.Incidents = sqlinfra.NewIncidentsRepo(st)
+	alerts.Silences = sqlinfra.NewSilencesRepo(st)
+	alerts.Outbox = sqlinfra.NewOutboxRepo(st)
+	chs, err := sqlinfra.NewChannelsRepo(st).ListChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("channels.List: %w", err)
+	}
+	// An invalid channel should not hold back the alerts of the others; its
+	// rules fall back to the default channels.
+	alerts.Channels, err = notinfr.NewChannelNotifiers(chs, tmpl)
+	if err != nil {
+		cw.log.Error("invalid notification channels", "error", err)
+	}
 	return alerts.SendAlerts(ctx)
 }
@@ -0,0 +1,286 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"gopkg.in/yaml.v3"
+)
+
+var _ port.Catalog = (*FileCatalog)(nil)
+
+// Price is a single catalog entry: Price (in Currency) is charged per UnitSize units.
+//
+// When Tiers are set they replace Price: each tier's price applies to the
+// cumulative units consumed in the billing month up to UpTo, and the last tier
+// applies to everything above. The first FreeUnits of every billing month are free.
+//
+// EffectiveFrom (inclusive) and EffectiveTo (exclusive) bound the period in which
+// the entry applies; either may be omitted for an open-ended range.
+type Price struct {
+	Service       string  `json:"service" yaml:"service"`
+	Metric        string  `json:"metric" yaml:"metric"`
+	Price         float64 `json:"price" yaml:"price"`
+	UnitSize      float64 `json:"unit_size" yaml:"unit_size"`
+	Currency      string  `json:"currency" yaml:"currency"`
+	FreeUnits     float64 `json:"free_units,omitempty" yaml:"free_units"`
+	Tiers         []Tier  `json:"tiers,omitempty" yaml:"tiers"`
+	EffectiveFrom Date    `json:"effective_from,omitzero" yaml:"effective_from"`
+	EffectiveTo   Date    `json:"effective_to,omitzero" yaml:"effective_to"`
+}
+
+// Tier prices the month-to-date units below UpTo (and above the previous tier).
+type Tier struct {
+	UpTo  float64 `json:"up_to,omitempty" yaml:"up_to"`
+	Price float64 `json:"price" yaml:"price"`
+}
+
+// Date is a catalog timestamp written either as 2006-01-02 or in RFC 3339.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) parse(s string) error {
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			d.Time = t.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", s)
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+func (d *Date) UnmarshalYAML(n *yaml.Node) error {
+	return d.parse(n.Value)
+}
+
+// covers reports whether the entry is effective at t.
+func (p Price) covers(t time.Time) bool {
+	if !p.EffectiveFrom.IsZero() && t.Before(p.EffectiveFrom.Time) {
+		return false
+	}
+	if !p.EffectiveTo.IsZero() && !t.Before(p.EffectiveTo.Time) {
+		return false
+	}
+	return true
+}
+
+func (p Price) version() port.PriceVersion {
+	tiers := make([]port.PriceTier, 0, len(p.Tiers))
+	for _, t := range p.Tiers {
+		tiers = append(tiers, port.PriceTier{UpTo: t.UpTo, Price: t.Price})
+	}
+	return port.PriceVersion{
+		Service:       p.Service,
+		Metric:        p.Metric,
+		EffectiveFrom: p.EffectiveFrom.Time,
+		EffectiveTo:   p.EffectiveTo.Time,
+		Price:         p.Price,
+		UnitSize:      p.UnitSize,
+		Currency:      p.Currency,
+		FreeUnits:     p.FreeUnits,
+		Tiers:         tiers,
+	}
+}
+
+// cost returns the charge for units consumed after monthToDate units in the same billing month.
+func (p Price) cost(monthToDate, units float64) float64 {
+	tiers := p.Tiers
+	if len(tiers) == 0 {
+		tiers = []Tier{{Price: p.Price}}
+	}
+
+	from, to := monthToDate, monthToDate+units
+	var (
+		total float64
+		lo    float64
+	)
+	for i, t := range tiers {
+		hi := t.UpTo
+		if i == len(tiers)-1 {
+			hi = math.Inf(1)
+		}
+		// Units within the free allowance are never charged.
+		segLo := math.Max(math.Max(lo, p.FreeUnits), from)
+		segHi := math.Min(hi, to)
+		if segHi > segLo {
+			total += (segHi - segLo) / p.UnitSize * t.Price
+		}
+		lo = hi
+	}
+
+	return total
+}
+
+func (p Price) validate() error {
+	if p.Service == "" || p.Metric == "" {
+		return fmt.Errorf("service and metric are required")
+	}
+	if p.UnitSize <= 0 {
+		return fmt.Errorf("%s/%s: unit_size must be positive", p.Service, p.Metric)
+	}
+	if p.FreeUnits < 0 {
+		return fmt.Errorf("%s/%s: free_units must not be negative", p.Service, p.Metric)
+	}
+	var prev float64
+	for i, t := range p.Tiers {
+		if i == len(p.Tiers)-1 {
+			break
+		}
+		if t.UpTo <= prev {
+			return fmt.Errorf("%s/%s: tier %d up_to must be greater than the previous tier", p.Service, p.Metric, i)
+		}
+		prev = t.UpTo
+	}
+	if !p.EffectiveFrom.IsZero() && !p.EffectiveTo.IsZero() && !p.EffectiveTo.After(p.EffectiveFrom.Time) {
+		return fmt.Errorf("%s/%s: effective_to must be after effective_from", p.Service, p.Metric)
+	}
+	return nil
+}
+
+type document struct {
+	Prices []Price `json:"prices" yaml:"prices"`
+}
+
+// FileCatalog implements port.Catalog from a YAML or JSON pricing file, e.g.:
+//
+//	prices:
+//	  - service: aws.CloudWatch
+//	    metric: IncomingBytes
+//	    price: 0.50
+//	    unit_size: 1000000000
+//	    currency: USD
+//	    free_units: 5000000000
+//	    effective_from: 2025-01-01
+//
+// A service/metric may be listed several times with non-overlapping effective
+// ranges to keep its price history. Prices apply to every account and region.
+// Lookups missing from the file, or outside every listed range, are resolved
+// through Fallback, if set.
+type FileCatalog struct {
+	Fallback port.Catalog
+	prices   map[string][]Price // key: service + "\x00" + metric, sorted by EffectiveFrom
+}
+
+// LoadFile parses the pricing file at path. The format is chosen by extension:
+// .json is decoded as JSON, anything else as YAML.
+func LoadFile(path string) (*FileCatalog, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog: %w", err)
+	}
+
+	var doc document
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(buf, &doc)
+	} else {
+		err = yaml.Unmarshal(buf, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode catalog %s: %w", path, err)
+	}
+
+	c := &FileCatalog{prices: make(map[string][]Price, len(doc.Prices))}
+	for i, p := range doc.Prices {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("catalog entry %d: %w", i, err)
+		}
+		key := p.Service + "\x00" + p.Metric
+		c.prices[key] = append(c.prices[key], p)
+	}
+
+	for _, versions := range c.prices {
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].EffectiveFrom.Before(versions[j].EffectiveFrom.Time)
+		})
+		for i := 1; i < len(versions); i++ {
+			prev, cur := versions[i-1], versions[i]
+			if prev.EffectiveTo.IsZero() || prev.EffectiveTo.After(cur.EffectiveFrom.Time) {
+				return nil, fmt.Errorf("catalog entries for %s/%s have overlapping effective ranges", cur.Service, cur.Metric)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func (c *FileCatalog) ComputeCost(service, metric string, at time.Time, units float64) (float64, bool) {
+	return c.ComputeMarginalCost(service, metric, at, 0, units)
+}
+
+func (c *FileCatalog) ComputeMarginalCost(service, metric string, at time.Time, monthToDate, units float64) (float64, bool) {
+	for _, p := range c.prices[service+"\x00"+metric] {
+		if p.covers(at) {
+			return math.Round(p.cost(monthToDate, units)*100) / 100, true
+		}
+	}
+
+	if c.Fallback != nil {
+		return c.Fallback.ComputeMarginalCost(service, metric, at, monthToDate, units)
+	}
+	return 0, false
+}
+
+// PriceVersions lists the file entries, followed by the Fallback versions of
+// service/metric pairs the file does not mention.
+func (c *FileCatalog) PriceVersions() []port.PriceVersion {
+	keys := make([]string, 0, len(c.prices))
+	for k := range c.prices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []port.PriceVersion
+	for _, k := range keys {
+		for _, p := range c.prices[k] {
+			out = append(out, p.version())
+		}
+	}
+
+	if c.Fallback != nil {
+		for _, v := range c.Fallback.PriceVersions() {
+			if _, ok := c.prices[v.Service+"\x00"+v.Metric]; !ok {
+				out = append(out, v)
+			}
+		}
+	}
+
+	return out
+}
+
+// NewFromEnv returns the catalog shared by the worker and the API. When
+// PRICING_CATALOG_PATH is set, prices are read from that file and anything it
+// does not cover falls back to the prices defined by the registered metrics.
+func NewFromEnv() (port.Catalog, error) {
+	path := os.Getenv("PRICING_CATALOG_PATH")
+	if path == "" {
+		return GlobalRegistryCatalog{}, nil
+	}
+
+	c, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c.Fallback = GlobalRegistryCatalog{}
+
+	return c, nil
+}
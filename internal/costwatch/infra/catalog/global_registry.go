@@ -1,9 +1,39 @@
 package catalog
 
-import "github.com/tailbits/costwatch/internal/costwatch"
+import (
+	"time"
 
+	"github.com/tailbits/costwatch/internal/costwatch"
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// GlobalRegistryCatalog prices metrics with the flat, undated prices defined by
+// the metrics registered in costwatch.
 type GlobalRegistryCatalog struct{}
 
-func (GlobalRegistryCatalog) ComputeCost(service, metric string, units float64) (float64, bool) {
+func (GlobalRegistryCatalog) ComputeCost(service, metric string, _ time.Time, units float64) (float64, bool) {
+	return costwatch.ComputeCost(service, metric, units)
+}
+
+// ComputeMarginalCost implements port.Catalog. Registry prices are flat, so
+// month-to-date usage does not affect the rate.
+func (GlobalRegistryCatalog) ComputeMarginalCost(service, metric string, _ time.Time, _, units float64) (float64, bool) {
 	return costwatch.ComputeCost(service, metric, units)
 }
+
+func (GlobalRegistryCatalog) PriceVersions() []port.PriceVersion {
+	var out []port.PriceVersion
+	for _, s := range costwatch.ListServices() {
+		for _, m := range s.Metrics() {
+			out = append(out, port.PriceVersion{
+				Service: s.Label(),
+				Metric:  m.Label(),
+				// Registry prices are expressed in cents.
+				Price:    m.Price() / 100,
+				UnitSize: m.UnitsPerPrice(),
+				Currency: "USD",
+			})
+		}
+	}
+	return out
+}
@@ -25,10 +25,11 @@ func NewMetricsRepo(db *clickstore.Client) *MetricsRepo {
 //go:embed sql/aggregate.sql
 var aggregateSQL string
 
-func (q *MetricsRepo) Aggregate(ctx context.Context, start, end time.Time, bucket time.Duration) ([]port.MetricBucket, error) {
+func (q *MetricsRepo) Aggregate(ctx context.Context, start, end time.Time, bucket time.Duration, mq port.MetricsQuery) ([]port.MetricBucket, error) {
 	var rows []struct {
 		Service   string    `ch:"service"`
 		Metric    string    `ch:"metric"`
+		Dimension string    `ch:"dimension"`
 		Timestamp time.Time `ch:"ts"`
 		Units     float64   `ch:"units"`
 	}
@@ -38,7 +39,12 @@ func (q *MetricsRepo) Aggregate(ctx context.Context, start, end time.Time, bucke
 		excludes = append(excludes, "coingecko")
 	}
 
-	if err := q.db.Select(ctx, &rows, aggregateSQL, int(bucket.Seconds()), start, end, excludes); err != nil {
+	if err := q.db.Select(ctx, &rows, aggregateSQL,
+		mq.GroupBy, mq.GroupBy, mq.GroupBy,
+		int(bucket.Seconds()), start, end, excludes,
+		mq.Service, mq.Service, mq.Metric, mq.Metric,
+		mq.Account, mq.Account, mq.Region, mq.Region,
+	); err != nil {
 		return nil, fmt.Errorf("clickhouse.Select: %w", err)
 	}
 
@@ -47,6 +53,7 @@ func (q *MetricsRepo) Aggregate(ctx context.Context, start, end time.Time, bucke
 		out = append(out, port.MetricBucket{
 			Service:   r.Service,
 			Metric:    r.Metric,
+			Dimension: r.Dimension,
 			Timestamp: r.Timestamp,
 			Units:     r.Units,
 		})
@@ -58,14 +65,15 @@ func (q *MetricsRepo) Aggregate(ctx context.Context, start, end time.Time, bucke
 //go:embed sql/percentiles.sql
 var percentilesSQL string
 
-func (q *MetricsRepo) Percentiles(ctx context.Context, start, end time.Time, bucket time.Duration) ([]port.MetricPercentiles, error) {
+func (q *MetricsRepo) Percentiles(ctx context.Context, start, end time.Time, bucket time.Duration, mq port.MetricsQuery) ([]port.MetricPercentiles, error) {
 	var rows []struct {
-		Service string  `ch:"service"`
-		Metric  string  `ch:"metric"`
-		P50     float64 `ch:"p50"`
-		P90     float64 `ch:"p90"`
-		P95     float64 `ch:"p95"`
-		PMax    float64 `ch:"pmax"`
+		Service   string  `ch:"service"`
+		Metric    string  `ch:"metric"`
+		Dimension string  `ch:"dimension"`
+		P50       float64 `ch:"p50"`
+		P90       float64 `ch:"p90"`
+		P95       float64 `ch:"p95"`
+		PMax      float64 `ch:"pmax"`
 	}
 
 	excludes := []string{}
@@ -73,19 +81,113 @@ func (q *MetricsRepo) Percentiles(ctx context.Context, start, end time.Time, buc
 		excludes = append(excludes, "coingecko")
 	}
 
-	if err := q.db.Select(ctx, &rows, percentilesSQL, start, end, int(bucket.Seconds()), excludes); err != nil {
+	if err := q.db.Select(ctx, &rows, percentilesSQL,
+		start, end, int(bucket.Seconds()),
+		mq.GroupBy, mq.GroupBy, mq.GroupBy,
+		mq.Service, mq.Service, mq.Metric, mq.Metric,
+		mq.Account, mq.Account, mq.Region, mq.Region,
+		excludes,
+	); err != nil {
 		return nil, fmt.Errorf("clickhouse.Select: %w", err)
 	}
 
 	out := make([]port.MetricPercentiles, 0, len(rows))
 	for _, r := range rows {
 		out = append(out, port.MetricPercentiles{
-			Service: r.Service,
-			Metric:  r.Metric,
-			P50:     r.P50,
-			P90:     r.P90,
-			P95:     r.P95,
-			PMax:    r.PMax,
+			Service:   r.Service,
+			Metric:    r.Metric,
+			Dimension: r.Dimension,
+			P50:       r.P50,
+			P90:       r.P90,
+			P95:       r.P95,
+			PMax:      r.PMax,
+		})
+	}
+
+	return out, nil
+}
+
+//go:embed sql/totals.sql
+var totalsSQL string
+
+func (q *MetricsRepo) Totals(ctx context.Context, start, end time.Time, mq port.MetricsQuery) ([]port.MetricTotal, error) {
+	var rows []struct {
+		Service   string  `ch:"service"`
+		Metric    string  `ch:"metric"`
+		Dimension string  `ch:"dimension"`
+		Units     float64 `ch:"units"`
+	}
+
+	excludes := []string{}
+	if disableDemo() {
+		excludes = append(excludes, "coingecko")
+	}
+
+	if err := q.db.Select(ctx, &rows, totalsSQL,
+		mq.GroupBy, mq.GroupBy, mq.GroupBy,
+		start, end, excludes,
+		mq.Service, mq.Service, mq.Metric, mq.Metric,
+		mq.Account, mq.Account, mq.Region, mq.Region,
+	); err != nil {
+		return nil, fmt.Errorf("clickhouse.Select: %w", err)
+	}
+
+	out := make([]port.MetricTotal, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, port.MetricTotal{
+			Service:   r.Service,
+			Metric:    r.Metric,
+			Dimension: r.Dimension,
+			Units:     r.Units,
+		})
+	}
+
+	return out, nil
+}
+
+//go:embed sql/baseline.sql
+var baselineSQL string
+
+func (q *MetricsRepo) Baseline(ctx context.Context, start, end time.Time, bucket time.Duration, mq port.MetricsQuery) ([]port.MetricBaseline, error) {
+	var rows []struct {
+		Service   string  `ch:"service"`
+		Metric    string  `ch:"metric"`
+		Dimension string  `ch:"dimension"`
+		Slot      int64   `ch:"slot"`
+		Samples   uint64  `ch:"samples"`
+		Mean      float64 `ch:"mean"`
+		StdDev    float64 `ch:"stddev"`
+		Median    float64 `ch:"med"`
+		MAD       float64 `ch:"mad"`
+	}
+
+	excludes := []string{}
+	if disableDemo() {
+		excludes = append(excludes, "coingecko")
+	}
+
+	if err := q.db.Select(ctx, &rows, baselineSQL,
+		int(bucket.Seconds()),
+		mq.GroupBy, mq.GroupBy, mq.GroupBy,
+		start, end, excludes,
+		mq.Service, mq.Service, mq.Metric, mq.Metric,
+		mq.Account, mq.Account, mq.Region, mq.Region,
+	); err != nil {
+		return nil, fmt.Errorf("clickhouse.Select: %w", err)
+	}
+
+	out := make([]port.MetricBaseline, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, port.MetricBaseline{
+			Service:   r.Service,
+			Metric:    r.Metric,
+			Dimension: r.Dimension,
+			Slot:      r.Slot,
+			Samples:   int(r.Samples),
+			Mean:      r.Mean,
+			StdDev:    r.StdDev,
+			Median:    r.Median,
+			MAD:       r.MAD,
 		})
 	}
 
@@ -1,6 +1,7 @@
 SELECT
   service,
   metric,
+  multiIf (? = 'account', account, ? = 'region', region, dimensions[?]) AS dimension,
   toStartOfInterval (timestamp, toIntervalSecond (?)) AS ts,
   sum(value) AS units
 FROM
@@ -9,11 +10,17 @@ WHERE
   timestamp >= ?
   AND timestamp < ?
   AND service NOT IN (?)
+  AND (? = '' OR service = ?)
+  AND (? = '' OR metric = ?)
+  AND (? = '' OR account = ?)
+  AND (? = '' OR region = ?)
 GROUP BY
   service,
   metric,
+  dimension,
   ts
 ORDER BY
   service,
   metric,
+  dimension,
   ts;
\ No newline at end of file
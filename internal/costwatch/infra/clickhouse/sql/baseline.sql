@@ -0,0 +1,47 @@
+WITH
+	toIntervalSecond (?) AS bucket,
+	by_bucket AS (
+		SELECT
+			service,
+			metric,
+			multiIf (? = 'account', account, ? = 'region', region, dimensions[?]) AS dimension,
+			toStartOfInterval (timestamp, bucket) AS ts,
+			sum(value) AS units
+		FROM
+			metrics FINAL
+		WHERE
+			timestamp >= ?
+			AND timestamp < ?
+			AND service NOT IN (?)
+			AND (? = '' OR service = ?)
+			AND (? = '' OR metric = ?)
+			AND (? = '' OR account = ?)
+			AND (? = '' OR region = ?)
+		GROUP BY
+			service,
+			metric,
+			dimension,
+			ts
+	)
+SELECT
+	service,
+	metric,
+	dimension,
+	toInt64 (toUnixTimestamp (toDateTime (ts)) % 604800) AS slot,
+	toUInt64 (count()) AS samples,
+	avg(units) AS mean,
+	if(count() > 1, stddevSamp (units), 0) AS stddev,
+	toFloat64 (quantileExact (0.5) (units)) AS med,
+	toFloat64 (arrayReduce ('quantileExact(0.5)', arrayMap (x -> abs(x - med), groupArray (units)))) AS mad
+FROM
+	by_bucket
+GROUP BY
+	service,
+	metric,
+	dimension,
+	slot
+ORDER BY
+	service,
+	metric,
+	dimension,
+	slot;
@@ -7,6 +7,7 @@ WITH
 		SELECT
 			service,
 			metric,
+			multiIf (? = 'account', account, ? = 'region', region, dimensions[?]) AS dimension,
 			toStartOfInterval (timestamp, bucket) AS bucket_ts,
 			sum(value) AS bucket_usage
 		FROM
@@ -14,14 +15,20 @@ WITH
 		WHERE
 			timestamp >= start_ts
 			AND timestamp < end_bucket
+			AND (? = '' OR service = ?)
+			AND (? = '' OR metric = ?)
+			AND (? = '' OR account = ?)
+			AND (? = '' OR region = ?)
 		GROUP BY
 			service,
 			metric,
+			dimension,
 			bucket_ts
 	)
 SELECT
 	service,
 	metric,
+	dimension,
 	toFloat64 (quantileTDigest (0.50) (bucket_usage)) AS p50,
 	toFloat64 (quantileTDigest (0.90) (bucket_usage)) AS p90,
 	toFloat64 (quantileTDigest (0.95) (bucket_usage)) AS p95,
@@ -32,7 +39,9 @@ WHERE
 	service NOT IN (?)
 GROUP BY
 	service,
-	metric
+	metric,
+	dimension
 ORDER BY
 	service,
-	metric;
\ No newline at end of file
+	metric,
+	dimension;
\ No newline at end of file
@@ -0,0 +1,23 @@
+SELECT
+  service,
+  metric,
+  multiIf (? = 'account', account, ? = 'region', region, dimensions[?]) AS dimension,
+  sum(value) AS units
+FROM
+  metrics FINAL
+WHERE
+  timestamp >= ?
+  AND timestamp < ?
+  AND service NOT IN (?)
+  AND (? = '' OR service = ?)
+  AND (? = '' OR metric = ?)
+  AND (? = '' OR account = ?)
+  AND (? = '' OR region = ?)
+GROUP BY
+  service,
+  metric,
+  dimension
+ORDER BY
+  service,
+  metric,
+  dimension;
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"time"
 
 	"github.com/tailbits/costwatch/internal/costwatch/port"
 )
@@ -13,18 +14,49 @@ import (
 //
 // It reads alert rules from the ALERT_RULES environment variable as a JSON array
 // of objects: [{"service":"aws.CloudWatch","metric":"IncomingBytes","threshold":0.47}].
-// This provider is read-only: UpsertRule returns an error. Notification state is ignored.
+// Budget rules set "kind":"budget" with a "period", an "amount", and optionally
+// "percentages" and a "basis". Anomaly rules set "kind":"anomaly" and
+// optionally a "method", "sensitivity" and "baseline_weeks". Baseline and change
+// rules set "kind":"baseline" or "kind":"change" with a "percent", and baseline
+// rules optionally a "percentile" and "baseline_days". Any rule may set a
+// "renotify_interval" and an "escalate_after" in seconds, a "critical" level,
+// email "recipients" and notification "channels". This provider is read-only:
+// UpsertRule returns an error.
+// Notification state (last notifications and notified budget percentages) is
+// kept by the state repo, or ignored if it is nil.
 //
 // Environment key: ALERT_RULES
 
-type AlertsRepos struct{}
+type AlertsRepos struct {
+	state port.AlertsRepo
+}
 
-func NewAlertsRepos() *AlertsRepos { return &AlertsRepos{} }
+func NewAlertsRepos(state port.AlertsRepo) *AlertsRepos { return &AlertsRepos{state: state} }
 
 type rule struct {
-	Service   string  `json:"service"`
-	Metric    string  `json:"metric"`
-	Threshold float64 `json:"threshold"`
+	Service     string    `json:"service"`
+	Metric      string    `json:"metric"`
+	Kind        string    `json:"kind"`
+	Threshold   float64   `json:"threshold"`
+	Period      string    `json:"period"`
+	Amount      float64   `json:"amount"`
+	Percentages []float64 `json:"percentages"`
+	Basis       string    `json:"basis"`
+
+	Method        string  `json:"method"`
+	Sensitivity   float64 `json:"sensitivity"`
+	BaselineWeeks int     `json:"baseline_weeks"`
+
+	Percent      float64 `json:"percent"`
+	Percentile   string  `json:"percentile"`
+	BaselineDays int     `json:"baseline_days"`
+
+	RenotifyInterval int     `json:"renotify_interval"`
+	Critical         float64 `json:"critical"`
+	EscalateAfter    int     `json:"escalate_after"`
+
+	Recipients []string `json:"recipients"`
+	Channels   []string `json:"channels"`
 }
 
 var ErrReadOnly = errors.New("env alerts repo is read-only")
@@ -40,10 +72,34 @@ func (r *AlertsRepos) ListRules(_ context.Context) ([]port.AlertRule, error) {
 	}
 	out := make([]port.AlertRule, 0, len(rs))
 	for _, it := range rs {
+		kind := port.RuleKind(it.Kind)
+		if kind == "" {
+			kind = port.RuleThreshold
+		}
 		out = append(out, port.AlertRule{
-			Service:   it.Service,
-			Metric:    it.Metric,
-			Threshold: it.Threshold,
+			Service:     it.Service,
+			Metric:      it.Metric,
+			Kind:        kind,
+			Threshold:   it.Threshold,
+			Period:      port.BudgetPeriod(it.Period),
+			Amount:      it.Amount,
+			Percentages: it.Percentages,
+			Basis:       port.BudgetBasis(it.Basis),
+
+			Method:        port.AnomalyMethod(it.Method),
+			Sensitivity:   it.Sensitivity,
+			BaselineWeeks: it.BaselineWeeks,
+
+			Percent:      it.Percent,
+			Percentile:   port.Percentile(it.Percentile),
+			BaselineDays: it.BaselineDays,
+
+			RenotifyInterval: time.Duration(it.RenotifyInterval) * time.Second,
+			Critical:         it.Critical,
+			EscalateAfter:    time.Duration(it.EscalateAfter) * time.Second,
+
+			Recipients: it.Recipients,
+			Channels:   it.Channels,
 		})
 	}
 	return out, nil
@@ -53,10 +109,30 @@ func (r *AlertsRepos) UpsertRule(_ context.Context, _ port.AlertRule) error {
 	return ErrReadOnly
 }
 
-func (r *AlertsRepos) GetLastNotified(_ context.Context, _, _ string) (int64, bool, error) {
-	return 0, false, nil
+func (r *AlertsRepos) GetLastNotified(ctx context.Context, k port.IncidentKey) (int64, bool, error) {
+	if r.state == nil {
+		return 0, false, nil
+	}
+	return r.state.GetLastNotified(ctx, k)
+}
+
+func (r *AlertsRepos) SetLastNotified(ctx context.Context, k port.IncidentKey, timeUnix int64) error {
+	if r.state == nil {
+		return nil
+	}
+	return r.state.SetLastNotified(ctx, k, timeUnix)
 }
 
-func (r *AlertsRepos) SetLastNotified(_ context.Context, _, _ string, _ int64) error {
-	return nil
+func (r *AlertsRepos) GetBudgetNotified(ctx context.Context, service, metric string, period port.BudgetPeriod, periodStart time.Time) (float64, error) {
+	if r.state == nil {
+		return 0, nil
+	}
+	return r.state.GetBudgetNotified(ctx, service, metric, period, periodStart)
+}
+
+func (r *AlertsRepos) SetBudgetNotified(ctx context.Context, service, metric string, period port.BudgetPeriod, periodStart time.Time, pct float64) error {
+	if r.state == nil {
+		return nil
+	}
+	return r.state.SetBudgetNotified(ctx, service, metric, period, periodStart, pct)
 }
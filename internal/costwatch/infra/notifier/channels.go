@@ -0,0 +1,237 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// WebhookConfig is the config of a webhook channel; Format, Headers and Secret
+// are like ALERT_WEBHOOK_FORMAT, ALERT_WEBHOOK_HEADERS and ALERT_WEBHOOK_SECRET.
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Format  string            `json:"format,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Secret  string            `json:"secret,omitempty"`
+}
+
+// PagerDutyConfig is the config of a PagerDuty channel. EventsURL defaults to
+// PagerDutyEventsURL.
+type PagerDutyConfig struct {
+	RoutingKey string `json:"routing_key"`
+	EventsURL  string `json:"events_url,omitempty"`
+}
+
+// EmailConfig is the config of an email channel. Port defaults to 587.
+type EmailConfig struct {
+	Host     string   `json:"host"`
+	Port     string   `json:"port,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to,omitempty"`
+}
+
+// NewChannelNotifier returns the notifier of a channel, rendering alerts with
+// tmpl and linking them to DASHBOARD_URL. It fails if the config of the
+// channel is invalid.
+func NewChannelNotifier(ch port.Channel, tmpl *Templates) (port.Notifier, error) {
+	dashboardURL := os.Getenv("DASHBOARD_URL")
+	switch ch.Type {
+	case port.ChannelWebhook:
+		var c WebhookConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook channel %s: url must be an http or https URL", ch.Name)
+		}
+		format, err := ParseWebhookFormat(c.Format)
+		if err != nil {
+			return nil, fmt.Errorf("webhook channel %s: %w", ch.Name, err)
+		}
+		n := NewWebhookNotifier(c.URL)
+		n.Format = format
+		n.Templates = tmpl
+		n.Secret = c.Secret
+		n.DashboardURL = dashboardURL
+		if len(c.Headers) > 0 {
+			n.Headers = make(map[string][]string, len(c.Headers))
+			for k, v := range c.Headers {
+				n.Headers.Set(k, v)
+			}
+		}
+		return n, nil
+	case port.ChannelPagerDuty:
+		var c PagerDutyConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		if c.RoutingKey == "" {
+			return nil, fmt.Errorf("pagerduty channel %s: routing_key is required", ch.Name)
+		}
+		n := NewPagerDutyNotifier(c.RoutingKey)
+		if c.EventsURL != "" {
+			n.URL = c.EventsURL
+		}
+		n.Templates = tmpl
+		return n, nil
+	case port.ChannelEmail:
+		var c EmailConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		if c.Host == "" {
+			return nil, fmt.Errorf("email channel %s: host is required", ch.Name)
+		}
+		if _, err := mail.ParseAddress(c.From); err != nil {
+			return nil, fmt.Errorf("email channel %s: from must be an email address, got %q", ch.Name, c.From)
+		}
+		for _, to := range c.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return nil, fmt.Errorf("email channel %s: to must be email addresses, got %q", ch.Name, to)
+			}
+		}
+		n := &EmailNotifier{
+			Host:         c.Host,
+			Port:         c.Port,
+			Username:     c.Username,
+			Password:     c.Password,
+			From:         c.From,
+			To:           c.To,
+			Templates:    tmpl,
+			DashboardURL: dashboardURL,
+		}
+		if n.Port == "" {
+			n.Port = "587"
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("channel %s: unknown type %q: must be webhook, pagerduty or email", ch.Name, ch.Type)
+}
+
+// NewChannelNotifiers returns the notifiers of channels by name. Channels with
+// an invalid config are left out, and reported in the error.
+func NewChannelNotifiers(chs []port.Channel, tmpl *Templates) (map[string]port.Notifier, error) {
+	out := make(map[string]port.Notifier, len(chs))
+	var errs []error
+	for _, ch := range chs {
+		n, err := NewChannelNotifier(ch, tmpl)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out[ch.Name] = n
+	}
+	return out, errors.Join(errs...)
+}
+
+// redacted replaces secrets in the configs returned by RedactChannelConfig.
+const redacted = "********"
+
+// RedactChannelConfig returns the config of a channel with its secrets (the
+// webhook secret and header values, the PagerDuty routing key and the SMTP
+// password) masked.
+func RedactChannelConfig(ch port.Channel) (json.RawMessage, error) {
+	switch ch.Type {
+	case port.ChannelWebhook:
+		var c WebhookConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		c.Secret = redact(c.Secret)
+		for k, v := range c.Headers {
+			c.Headers[k] = redact(v)
+		}
+		return json.Marshal(c)
+	case port.ChannelPagerDuty:
+		var c PagerDutyConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		c.RoutingKey = redact(c.RoutingKey)
+		return json.Marshal(c)
+	case port.ChannelEmail:
+		var c EmailConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		c.Password = redact(c.Password)
+		return json.Marshal(c)
+	}
+	return ch.Config, nil
+}
+
+func redact(v string) string {
+	if v == "" {
+		return ""
+	}
+	return redacted
+}
+
+// KeepChannelSecrets returns the config of ch with the masked secrets of
+// RedactChannelConfig replaced by those of prev, the stored channel, so that a
+// redacted config can be written back.
+func KeepChannelSecrets(ch, prev port.Channel) (json.RawMessage, error) {
+	if prev.Type != ch.Type {
+		return ch.Config, nil
+	}
+	switch ch.Type {
+	case port.ChannelWebhook:
+		var c, p WebhookConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		_ = decodeConfig(prev, &p)
+		c.Secret = keepSecret(c.Secret, p.Secret)
+		for k, v := range c.Headers {
+			c.Headers[k] = keepSecret(v, p.Headers[k])
+		}
+		return json.Marshal(c)
+	case port.ChannelPagerDuty:
+		var c, p PagerDutyConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		_ = decodeConfig(prev, &p)
+		c.RoutingKey = keepSecret(c.RoutingKey, p.RoutingKey)
+		return json.Marshal(c)
+	case port.ChannelEmail:
+		var c, p EmailConfig
+		if err := decodeConfig(ch, &c); err != nil {
+			return nil, err
+		}
+		_ = decodeConfig(prev, &p)
+		c.Password = keepSecret(c.Password, p.Password)
+		return json.Marshal(c)
+	}
+	return ch.Config, nil
+}
+
+func keepSecret(v, prev string) string {
+	if v == redacted {
+		return prev
+	}
+	return v
+}
+
+// decodeConfig decodes the config of a channel into v, rejecting unknown
+// fields.
+func decodeConfig(ch port.Channel, v any) error {
+	if len(ch.Config) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(ch.Config))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s channel %s: invalid config: %w", ch.Type, ch.Name, err)
+	}
+	return nil
+}
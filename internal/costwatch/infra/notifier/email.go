@@ -0,0 +1,212 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// smtpTimeout bounds an SMTP session when the context has no deadline.
+const smtpTimeout = time.Minute
+
+// EmailNotifier sends alerts over SMTP to To and to the recipients of the rule
+// of each alert. It upgrades the connection with STARTTLS when the server
+// offers it, and authenticates when Username is set. Emails have a plain-text
+// and an HTML body, which link to DashboardURL if set.
+type EmailNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	Templates    *Templates
+	DashboardURL string
+}
+
+// NewEmailNotifierFromEnv reads the SMTP server from SMTP_HOST, SMTP_PORT
+// (default 587), SMTP_USERNAME and SMTP_PASSWORD, the sender and default
+// recipients (comma separated) from ALERT_EMAIL_FROM and ALERT_EMAIL_TO, and
+// the dashboard link from DASHBOARD_URL.
+func NewEmailNotifierFromEnv(tmpl *Templates) *EmailNotifier {
+	n := &EmailNotifier{
+		Host:         os.Getenv("SMTP_HOST"),
+		Port:         os.Getenv("SMTP_PORT"),
+		Username:     os.Getenv("SMTP_USERNAME"),
+		Password:     os.Getenv("SMTP_PASSWORD"),
+		From:         os.Getenv("ALERT_EMAIL_FROM"),
+		Templates:    tmpl,
+		DashboardURL: os.Getenv("DASHBOARD_URL"),
+	}
+	if n.Port == "" {
+		n.Port = "587"
+	}
+	for _, to := range strings.Split(os.Getenv("ALERT_EMAIL_TO"), ",") {
+		if to = strings.TrimSpace(to); to != "" {
+			n.To = append(n.To, to)
+		}
+	}
+	return n
+}
+
+// Configured reports whether the notifier has an SMTP server.
+func (n *EmailNotifier) Configured() bool { return n.Host != "" }
+
+// Send emails an alert to the default recipients and those of its rule. The
+// subject and bodies are rendered with the "email_subject", "email_text" and
+// "email_html" templates.
+func (n *EmailNotifier) Send(ctx context.Context, ev port.AlertEvent) error {
+	if n.Host == "" {
+		return nil
+	}
+	subject, err := n.Templates.Render("email_subject", ev, n.DashboardURL)
+	if err != nil {
+		return err
+	}
+	text, err := n.Templates.Render("email_text", ev, n.DashboardURL)
+	if err != nil {
+		return err
+	}
+	html, err := n.Templates.RenderHTML("email_html", ev, n.DashboardURL)
+	if err != nil {
+		return err
+	}
+	to := append(append([]string(nil), n.To...), ev.Rule.Recipients...)
+	return n.send(ctx, to, strings.TrimSpace(subject), text, html)
+}
+
+func (n *EmailNotifier) send(ctx context.Context, to []string, subject, text, html string) error {
+	from, err := mail.ParseAddress(n.From)
+	if err != nil {
+		return fmt.Errorf("email: invalid sender %q: %w", n.From, err)
+	}
+	var rcpts []*mail.Address
+	seen := make(map[string]bool)
+	for _, it := range to {
+		addr, err := mail.ParseAddress(it)
+		if err != nil {
+			return fmt.Errorf("email: invalid recipient %q: %w", it, err)
+		}
+		if !seen[strings.ToLower(addr.Address)] {
+			seen[strings.ToLower(addr.Address)] = true
+			rcpts = append(rcpts, addr)
+		}
+	}
+	if len(rcpts) == 0 {
+		return nil
+	}
+
+	msg, err := buildEmail(from, rcpts, subject, text, html)
+	if err != nil {
+		return err
+	}
+
+	d := net.Dialer{Timeout: smtpTimeout}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(n.Host, n.Port))
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.Host}); err != nil {
+			return err
+		}
+	}
+	if n.Username != "" {
+		// PlainAuth refuses to send the password over a connection that is
+		// neither encrypted nor to localhost.
+		if err := c.Auth(smtp.PlainAuth("", n.Username, n.Password, n.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, r := range rcpts {
+		if err := c.Rcpt(r.Address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmail renders a multipart/alternative message with a plain-text and an
+// HTML body.
+func buildEmail(from *mail.Address, to []*mail.Address, subject, text, html string) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=utf-8", []byte(text)},
+		{"text/html; charset=utf-8", []byte(html)},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	tos := make([]string, 0, len(to))
+	for _, addr := range to {
+		tos = append(tos, addr.String())
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(tos, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
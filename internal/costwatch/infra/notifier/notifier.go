@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// NewFromEnv returns the notifier selected by ALERT_NOTIFIER, "webhook" (the
+// default), "pagerduty" or "email", along with its name. Alerts are rendered
+// with tmpl, or the default templates if nil.
+func NewFromEnv(tmpl *Templates) (port.Notifier, string, error) {
+	switch kind := os.Getenv("ALERT_NOTIFIER"); kind {
+	case "", "webhook":
+		if _, err := ParseWebhookFormat(os.Getenv("ALERT_WEBHOOK_FORMAT")); err != nil {
+			return nil, "", err
+		}
+		if _, err := webhookHeadersFromEnv(); err != nil {
+			return nil, "", err
+		}
+		return NewWebhookNotifierFromEnv(tmpl), "webhook", nil
+	case "pagerduty":
+		n := NewPagerDutyNotifierFromEnv(tmpl)
+		if n.RoutingKey == "" {
+			return nil, "", fmt.Errorf("PAGERDUTY_ROUTING_KEY is required by the pagerduty notifier")
+		}
+		return n, kind, nil
+	case "email":
+		n := NewEmailNotifierFromEnv(tmpl)
+		if n.Host == "" || n.From == "" {
+			return nil, "", fmt.Errorf("SMTP_HOST and ALERT_EMAIL_FROM are required by the email notifier")
+		}
+		return n, kind, nil
+	default:
+		return nil, "", fmt.Errorf("unknown ALERT_NOTIFIER %q: must be webhook, pagerduty or email", kind)
+	}
+}
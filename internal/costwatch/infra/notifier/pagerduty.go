@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"unicode/utf8"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// PagerDutyEventsURL is the endpoint of the PagerDuty Events API v2.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// maxSummary is the longest summary PagerDuty accepts.
+const maxSummary = 1024
+
+// PagerDutyNotifier triggers and resolves PagerDuty alerts through the Events
+// API v2 of the service integration with RoutingKey. URL defaults to
+// PagerDutyEventsURL.
+type PagerDutyNotifier struct {
+	RoutingKey string
+	URL        string
+	Client     *http.Client
+	Templates  *Templates
+}
+
+var _ port.AlertTracker = (*PagerDutyNotifier)(nil)
+
+func NewPagerDutyNotifier(routingKey string) *PagerDutyNotifier {
+	return &PagerDutyNotifier{RoutingKey: routingKey, URL: PagerDutyEventsURL, Client: &http.Client{}}
+}
+
+// NewPagerDutyNotifierFromEnv reads the routing key from PAGERDUTY_ROUTING_KEY,
+// and the endpoint from PAGERDUTY_EVENTS_URL, if set.
+func NewPagerDutyNotifierFromEnv(tmpl *Templates) *PagerDutyNotifier {
+	n := NewPagerDutyNotifier(os.Getenv("PAGERDUTY_ROUTING_KEY"))
+	n.Templates = tmpl
+	if url := os.Getenv("PAGERDUTY_EVENTS_URL"); url != "" {
+		n.URL = url
+	}
+	return n
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary  string `json:"summary"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+// TracksAlerts reports that PagerDuty keeps alerts open until they resolve.
+func (n *PagerDutyNotifier) TracksAlerts() bool { return true }
+
+// Configured reports whether the notifier has a routing key.
+func (n *PagerDutyNotifier) Configured() bool { return n.RoutingKey != "" }
+
+// Send triggers the alert of ev.DedupKey, or resolves it. The summary of
+// alerts is rendered with the "pagerduty" template.
+func (n *PagerDutyNotifier) Send(ctx context.Context, ev port.AlertEvent) error {
+	if n.RoutingKey == "" {
+		return nil
+	}
+	body := pagerDutyEvent{RoutingKey: n.RoutingKey, EventAction: "trigger", DedupKey: ev.DedupKey}
+	if ev.Resolved() {
+		body.EventAction = "resolve"
+	} else {
+		sev := ev.Severity
+		if sev == "" {
+			sev = port.SeverityWarning
+		}
+		summary, err := n.Templates.Render("pagerduty", ev, "")
+		if err != nil {
+			return err
+		}
+		if len(summary) > maxSummary {
+			// Cut on a rune boundary, so the summary stays valid UTF-8.
+			n := maxSummary
+			for n > 0 && !utf8.RuneStart(summary[n]) {
+				n--
+			}
+			summary = summary[:n]
+		}
+		body.Payload = &pagerDutyPayload{Summary: summary, Source: "costwatch", Severity: string(sev)}
+	}
+
+	buf, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError("pagerduty", resp)
+	}
+	return nil
+}
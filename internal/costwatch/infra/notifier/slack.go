@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// slackMessage is a Slack Block Kit message. Text is shown in notifications
+// and by clients that cannot display blocks.
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string        `json:"type"`
+	Text     *slackObject  `json:"text,omitempty"`
+	Fields   []slackObject `json:"fields,omitempty"`
+	Elements []any         `json:"elements,omitempty"`
+}
+
+type slackObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackButton struct {
+	Type string      `json:"type"`
+	Text slackObject `json:"text"`
+	URL  string      `json:"url"`
+}
+
+func slackField(name, value string) slackObject {
+	return slackObject{Type: "mrkdwn", Text: "*" + name + "*\n" + value}
+}
+
+// slackAlert lays an alert out as a header, its text, a field per detail, a
+// sparkline of its hourly costs and a link to the dashboard.
+func slackAlert(ev port.AlertEvent, text, dashboardURL string) slackMessage {
+	fields := []slackObject{
+		slackField("Service", ev.Service),
+		slackField("Metric", ev.Metric),
+	}
+	if ev.Dimension != "" {
+		fields = append(fields, slackField("Dimension", ev.Dimension))
+	}
+	fields = append(fields, slackField("Window", ev.Window()))
+	if !ev.Resolved() {
+		fields = append(fields,
+			slackField("Expected", fmt.Sprintf("$%.2f", ev.Expected)),
+			slackField("Actual", fmt.Sprintf("$%.2f", ev.Actual)),
+		)
+	}
+	if ev.Severity != "" {
+		fields = append(fields, slackField("Severity", string(ev.Severity)))
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackObject{Type: "plain_text", Text: ev.Title()}},
+		{Type: "section", Text: &slackObject{Type: "mrkdwn", Text: text}},
+		{Type: "section", Fields: fields},
+	}
+	if line := sparkline(ev.Costs); line != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []any{
+			slackObject{Type: "mrkdwn", Text: "Hourly cost " + line},
+		}})
+	}
+	if dashboardURL != "" {
+		blocks = append(blocks, slackBlock{Type: "actions", Elements: []any{
+			slackButton{Type: "button", Text: slackObject{Type: "plain_text", Text: "Open dashboard"}, URL: dashboardURL},
+		}})
+	}
+	return slackMessage{Text: text, Blocks: blocks}
+}
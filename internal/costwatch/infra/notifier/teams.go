@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// teamsPayload is a Microsoft Teams message carrying an Adaptive Card, as
+// accepted by Teams incoming webhooks and workflows.
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+	Actions []teamsAction  `json:"actions,omitempty"`
+}
+
+type teamsElement struct {
+	Type     string      `json:"type"`
+	Text     string      `json:"text,omitempty"`
+	Wrap     bool        `json:"wrap,omitempty"`
+	Weight   string      `json:"weight,omitempty"`
+	Size     string      `json:"size,omitempty"`
+	Color    string      `json:"color,omitempty"`
+	FontType string      `json:"fontType,omitempty"`
+	Facts    []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type teamsAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// teamsAlert lays an alert out as a title colored by severity, its text, a
+// fact per detail, a sparkline of its hourly costs and a link to the dashboard.
+func teamsAlert(ev port.AlertEvent, text, dashboardURL string) teamsPayload {
+	color := "Warning"
+	switch {
+	case ev.Resolved():
+		color = "Good"
+	case ev.Severity == port.SeverityCritical:
+		color = "Attention"
+	}
+
+	facts := []teamsFact{
+		{Title: "Service", Value: ev.Service},
+		{Title: "Metric", Value: ev.Metric},
+	}
+	if ev.Dimension != "" {
+		facts = append(facts, teamsFact{Title: "Dimension", Value: ev.Dimension})
+	}
+	facts = append(facts, teamsFact{Title: "Window", Value: ev.Window()})
+	if !ev.Resolved() {
+		facts = append(facts,
+			teamsFact{Title: "Expected", Value: fmt.Sprintf("$%.2f", ev.Expected)},
+			teamsFact{Title: "Actual", Value: fmt.Sprintf("$%.2f", ev.Actual)},
+		)
+	}
+	if ev.Severity != "" {
+		facts = append(facts, teamsFact{Title: "Severity", Value: string(ev.Severity)})
+	}
+
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []teamsElement{
+			{Type: "TextBlock", Text: ev.Title(), Weight: "Bolder", Size: "Medium", Color: color, Wrap: true},
+			{Type: "TextBlock", Text: text, Wrap: true},
+			{Type: "FactSet", Facts: facts},
+		},
+	}
+	if line := sparkline(ev.Costs); line != "" {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: "Hourly cost " + line, FontType: "Monospace", Wrap: true})
+	}
+	if dashboardURL != "" {
+		card.Actions = []teamsAction{{Type: "Action.OpenUrl", Title: "Open dashboard", URL: dashboardURL}}
+	}
+	return teamsPayload{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: card}},
+	}
+}
@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+//go:embed templates/alerts.tmpl
+var defaultTemplates string
+
+// Templates render the text of notifications with Go templates, one per
+// channel: "webhook", "webhook_body", "slack", "teams", "pagerduty",
+// "email_subject", "email_text" and "email_html" (an html/template). Templates
+// are executed with the port.AlertEvent of an alert and the DashboardURL, and
+// can use the json, money, duration and sparkline functions.
+type Templates struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+// templateData is what templates are executed with: the fields and methods of
+// the event, e.g. {{.Service}} or {{.Summary}}, and {{.DashboardURL}}.
+type templateData struct {
+	port.AlertEvent
+	DashboardURL string
+}
+
+var templateFuncs = map[string]any{
+	"json":      jsonValue,
+	"money":     func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"duration":  port.FormatDuration,
+	"sparkline": sparkline,
+}
+
+// defaults are the built-in templates, also used by notifiers without
+// Templates.
+var defaults = func() *Templates {
+	t, err := parseTemplates("")
+	if err != nil {
+		panic(err) // the built-in templates are valid
+	}
+	return t
+}()
+
+// DefaultTemplates returns the built-in templates.
+func DefaultTemplates() *Templates {
+	return defaults
+}
+
+// LoadTemplates returns the built-in templates, overridden by the templates
+// defined in the file at path, if set.
+func LoadTemplates(path string) (*Templates, error) {
+	if path == "" {
+		return DefaultTemplates(), nil
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read alert templates: %w", err)
+	}
+	t, err := parseTemplates(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parse alert templates %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// TemplatesFromEnv loads the templates of ALERT_TEMPLATES_PATH.
+func TemplatesFromEnv() (*Templates, error) {
+	return LoadTemplates(os.Getenv("ALERT_TEMPLATES_PATH"))
+}
+
+func parseTemplates(overrides string) (*Templates, error) {
+	text, err := template.New("alerts").Funcs(templateFuncs).Parse(defaultTemplates)
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("alerts").Funcs(templateFuncs).Parse(defaultTemplates)
+	if err != nil {
+		return nil, err
+	}
+	if overrides != "" {
+		if _, err := text.New("overrides").Parse(overrides); err != nil {
+			return nil, err
+		}
+		if _, err := html.New("overrides").Parse(overrides); err != nil {
+			return nil, err
+		}
+	}
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render executes the template name for an alert.
+func (t *Templates) Render(name string, ev port.AlertEvent, dashboardURL string) (string, error) {
+	if t == nil {
+		t = defaults
+	}
+	var b strings.Builder
+	if err := t.text.ExecuteTemplate(&b, name, templateData{AlertEvent: ev, DashboardURL: dashboardURL}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderHTML executes the HTML template name for an alert.
+func (t *Templates) RenderHTML(name string, ev port.AlertEvent, dashboardURL string) (string, error) {
+	if t == nil {
+		t = defaults
+	}
+	var b strings.Builder
+	if err := t.html.ExecuteTemplate(&b, name, templateData{AlertEvent: ev, DashboardURL: dashboardURL}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// jsonValue encodes v as JSON, to write values into JSON templates, e.g.
+// {"summary": {{json .Summary}}}.
+func jsonValue(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// sparkBars are the bars of a sparkline, from the lowest to the highest cost.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws costs as a line of bars, e.g. "▁▃█▅".
+func sparkline(costs []float64) string {
+	if len(costs) == 0 {
+		return ""
+	}
+	lo, hi := costs[0], costs[0]
+	for _, c := range costs {
+		lo, hi = min(lo, c), max(hi, c)
+	}
+	var b strings.Builder
+	for _, c := range costs {
+		i := len(sparkBars) - 1
+		if hi > lo {
+			i = int((c - lo) / (hi - lo) * float64(len(sparkBars)-1))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
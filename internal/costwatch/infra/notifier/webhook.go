@@ -3,38 +3,223 @@ package notifier
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+)
+
+// WebhookFormat is the payload format of a WebhookNotifier.
+type WebhookFormat string
+
+const (
+	// FormatText posts {"text": ...}, understood by Slack and most
+	// Slack-compatible systems.
+	FormatText WebhookFormat = "text"
+	// FormatSlack posts Slack Block Kit messages.
+	FormatSlack WebhookFormat = "slack"
+	// FormatTeams posts Microsoft Teams Adaptive Cards.
+	FormatTeams WebhookFormat = "teams"
+	// FormatTemplate posts the JSON rendered by the "webhook_body" template.
+	FormatTemplate WebhookFormat = "template"
+)
+
+// Signed webhooks carry the Unix time they were sent at in TimestampHeader,
+// and in SignatureHeader "sha256=" and the hex HMAC-SHA256, keyed with the
+// secret, of the timestamp, a dot and the body. Receivers recompute it to
+// authenticate the request, and reject old timestamps to prevent replays.
+const (
+	TimestampHeader = "X-CostWatch-Timestamp"
+	SignatureHeader = "X-CostWatch-Signature"
 )
 
+// ParseWebhookFormat parses a format name; the empty name is FormatText.
+func ParseWebhookFormat(v string) (WebhookFormat, error) {
+	switch f := WebhookFormat(v); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatSlack, FormatTeams, FormatTemplate:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown webhook format %q: must be text, slack, teams or template", v)
+}
+
+// WebhookNotifier posts alerts to URL in Format. The text of alerts is
+// rendered with the "webhook", "slack" or "teams" template, following Format,
+// and the whole body with "webhook_body" in FormatTemplate. Slack and Teams
+// messages link to DashboardURL if set. Requests carry Headers, and are signed
+// with Secret if set.
 type WebhookNotifier struct {
-	URL    string
-	Client *http.Client
+	URL       string
+	Format    WebhookFormat
+	Client    *http.Client
+	Templates *Templates
+	Headers   http.Header
+	Secret    string
+
+	DashboardURL string
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Format: FormatText, Client: &http.Client{}}
 }
 
-func NewWebhookNotifierFromEnv() *WebhookNotifier {
-	return &WebhookNotifier{URL: os.Getenv("ALERT_WEBHOOK_URL"), Client: &http.Client{}}
+// NewWebhookNotifierFromEnv reads the URL from ALERT_WEBHOOK_URL.
+func NewWebhookNotifierFromEnv(tmpl *Templates) *WebhookNotifier {
+	return newWebhookNotifierFromEnv(os.Getenv("ALERT_WEBHOOK_URL"), tmpl)
 }
 
-func (n *WebhookNotifier) Send(ctx context.Context, text string) error {
+// newWebhookNotifierFromEnv returns a notifier posting to url in the format
+// of ALERT_WEBHOOK_FORMAT, linking to DASHBOARD_URL, with the headers of
+// ALERT_WEBHOOK_HEADERS and signed with ALERT_WEBHOOK_SECRET. An unknown
+// format is reported by Send, and invalid headers are ignored: NewFromEnv
+// reports both.
+func newWebhookNotifierFromEnv(url string, tmpl *Templates) *WebhookNotifier {
+	n := NewWebhookNotifier(url)
+	if v := os.Getenv("ALERT_WEBHOOK_FORMAT"); v != "" {
+		n.Format = WebhookFormat(v)
+	}
+	n.Templates = tmpl
+	n.Headers, _ = webhookHeadersFromEnv()
+	n.Secret = os.Getenv("ALERT_WEBHOOK_SECRET")
+	n.DashboardURL = os.Getenv("DASHBOARD_URL")
+	return n
+}
+
+// webhookHeadersFromEnv parses ALERT_WEBHOOK_HEADERS, a JSON object of header
+// names and values, e.g. {"Authorization": "Bearer ..."}.
+func webhookHeadersFromEnv() (http.Header, error) {
+	v := os.Getenv("ALERT_WEBHOOK_HEADERS")
+	if v == "" {
+		return nil, nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(v), &m); err != nil {
+		return nil, fmt.Errorf("ALERT_WEBHOOK_HEADERS must be a JSON object of header names and values: %w", err)
+	}
+	h := make(http.Header, len(m))
+	for k, v := range m {
+		h.Set(k, v)
+	}
+	return h, nil
+}
+
+// NewSeverityNotifiersFromEnv returns a webhook notifier for each severity
+// with a URL in ALERT_WARNING_WEBHOOK_URL or ALERT_CRITICAL_WEBHOOK_URL.
+func NewSeverityNotifiersFromEnv(tmpl *Templates) map[port.Severity]port.Notifier {
+	out := make(map[port.Severity]port.Notifier)
+	for sev, env := range map[port.Severity]string{
+		port.SeverityWarning:  "ALERT_WARNING_WEBHOOK_URL",
+		port.SeverityCritical: "ALERT_CRITICAL_WEBHOOK_URL",
+	} {
+		if url := os.Getenv(env); url != "" {
+			out[sev] = newWebhookNotifierFromEnv(url, tmpl)
+		}
+	}
+	return out
+}
+
+// Configured reports whether the webhook has a URL.
+func (n *WebhookNotifier) Configured() bool { return n.URL != "" }
+
+// Send posts an alert, with its details in the Slack and Teams formats.
+func (n *WebhookNotifier) Send(ctx context.Context, ev port.AlertEvent) error {
 	if n.URL == "" {
 		return nil
 	}
-	payload := map[string]string{"text": text}
-	buf, _ := json.Marshal(payload)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(buf))
+	format, err := ParseWebhookFormat(string(n.Format))
+	if err != nil {
+		return err
+	}
+	var name string
+	switch format {
+	case FormatText:
+		name = "webhook"
+	case FormatTemplate:
+		name = "webhook_body"
+	default:
+		name = string(format)
+	}
+	text, err := n.Templates.Render(name, ev, n.DashboardURL)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	switch format {
+	case FormatSlack:
+		body, err = json.Marshal(slackAlert(ev, text, n.DashboardURL))
+	case FormatTeams:
+		body, err = json.Marshal(teamsAlert(ev, text, n.DashboardURL))
+	case FormatTemplate:
+		body = []byte(text)
+		if !json.Valid(body) {
+			err = fmt.Errorf("webhook: the webhook_body template rendered invalid JSON: %q", text)
+		}
+	default:
+		body, err = json.Marshal(map[string]string{"text": text})
+	}
+	if err != nil {
+		return err
+	}
+	return n.post(ctx, body)
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for k, vs := range n.Headers {
+		req.Header[k] = vs
+	}
+	if n.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.Secret, ts, body))
+	}
 	resp, err := n.Client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil
+		return responseError("webhook", resp)
 	}
 	return nil
 }
+
+// Sign returns the hex HMAC-SHA256 of a webhook body sent at timestamp, as set
+// in SignatureHeader after "sha256=".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// maxErrorBody caps how much of the body of a failed response is reported.
+const maxErrorBody = 512
+
+// responseError reports a non-2xx response with the start of its body, which
+// usually tells why the request was refused.
+func responseError(name string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &port.ResponseError{
+		Notifier:   name,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
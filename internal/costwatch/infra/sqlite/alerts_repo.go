@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tailbits/costwatch/internal/costwatch/port"
@@ -30,10 +33,28 @@ func (r *AlertsRepos) ListRules(ctx context.Context) ([]port.AlertRule, error) {
 	defer rows.Close()
 	var out []port.AlertRule
 	for rows.Next() {
-		var rec port.AlertRule
-		if err := rows.Scan(&rec.Service, &rec.Metric, &rec.Threshold); err != nil {
+		var (
+			rec      port.AlertRule
+			pcts     string
+			renotify int64
+			escalate int64
+			rcpts    string
+			chans    string
+		)
+		if err := rows.Scan(&rec.Service, &rec.Metric, &rec.Kind, &rec.Period, &rec.Threshold, &rec.Amount, &pcts, &rec.Basis, &rec.Method, &rec.Sensitivity, &rec.BaselineWeeks, &rec.Percent, &rec.Percentile, &rec.BaselineDays, &renotify, &rec.Critical, &escalate, &rcpts, &chans); err != nil {
 			return nil, err
 		}
+		rec.RenotifyInterval = time.Duration(renotify) * time.Second
+		rec.EscalateAfter = time.Duration(escalate) * time.Second
+		if rec.Percentages, err = parsePercentages(pcts); err != nil {
+			return nil, err
+		}
+		if rcpts != "" {
+			rec.Recipients = strings.Split(rcpts, ",")
+		}
+		if chans != "" {
+			rec.Channels = strings.Split(chans, ",")
+		}
 		out = append(out, rec)
 	}
 	if err := rows.Err(); err != nil {
@@ -46,16 +67,51 @@ func (r *AlertsRepos) ListRules(ctx context.Context) ([]port.AlertRule, error) {
 var upsertAlertRuleSQL string
 
 func (r *AlertsRepos) UpsertRule(ctx context.Context, ar port.AlertRule) error {
-	_, err := r.st.DB().ExecContext(ctx, upsertAlertRuleSQL, ar.Service, ar.Metric, ar.Threshold)
+	kind := ar.Kind
+	if kind == "" {
+		kind = port.RuleThreshold
+	}
+	_, err := r.st.DB().ExecContext(ctx, upsertAlertRuleSQL,
+		ar.Service, ar.Metric, kind, ar.Period,
+		ar.Threshold, ar.Amount, formatPercentages(ar.Percentages), ar.Basis,
+		ar.Method, ar.Sensitivity, ar.BaselineWeeks,
+		ar.Percent, ar.Percentile, ar.BaselineDays,
+		int64(ar.RenotifyInterval/time.Second), ar.Critical, int64(ar.EscalateAfter/time.Second),
+		strings.Join(ar.Recipients, ","), strings.Join(ar.Channels, ","),
+	)
 	return err
 }
 
+// Percentages are stored as a comma separated list, e.g. "50,80,100".
+func formatPercentages(pcts []float64) string {
+	parts := make([]string, 0, len(pcts))
+	for _, p := range pcts {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+func parsePercentages(v string) ([]float64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	var out []float64
+	for _, it := range strings.Split(v, ",") {
+		p, err := strconv.ParseFloat(it, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget percentage %q: %w", it, err)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
 //go:embed sql/get_last_notified.sql
 var getLastNotified string
 
 // Notifications
-func (r *AlertsRepos) GetLastNotified(ctx context.Context, s, m string) (int64, bool, error) {
-	row := r.st.DB().QueryRowContext(ctx, getLastNotified, s, m)
+func (r *AlertsRepos) GetLastNotified(ctx context.Context, k port.IncidentKey) (int64, bool, error) {
+	row := r.st.DB().QueryRowContext(ctx, getLastNotified, k.Service, k.Metric, string(k.Kind), string(k.Period), k.Dimension)
 	var ts sql.NullTime
 	if err := row.Scan(&ts); err != nil {
 		if err == sql.ErrNoRows {
@@ -72,7 +128,31 @@ func (r *AlertsRepos) GetLastNotified(ctx context.Context, s, m string) (int64,
 //go:embed sql/set_last_notified.sql
 var setLastNotified string
 
-func (r *AlertsRepos) SetLastNotified(ctx context.Context, s, m string, unix int64) error {
-	_, err := r.st.DB().ExecContext(ctx, setLastNotified, time.Unix(unix, 0).UTC(), s, m)
+func (r *AlertsRepos) SetLastNotified(ctx context.Context, k port.IncidentKey, unix int64) error {
+	_, err := r.st.DB().ExecContext(ctx, setLastNotified, k.Service, k.Metric, string(k.Kind), string(k.Period), k.Dimension, time.Unix(unix, 0).UTC())
+	return err
+}
+
+//go:embed sql/get_budget_notified.sql
+var getBudgetNotified string
+
+// Budgets
+func (r *AlertsRepos) GetBudgetNotified(ctx context.Context, s, m string, period port.BudgetPeriod, periodStart time.Time) (float64, error) {
+	row := r.st.DB().QueryRowContext(ctx, getBudgetNotified, s, m, period, periodStart.UTC())
+	var pct float64
+	if err := row.Scan(&pct); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return pct, nil
+}
+
+//go:embed sql/set_budget_notified.sql
+var setBudgetNotified string
+
+func (r *AlertsRepos) SetBudgetNotified(ctx context.Context, s, m string, period port.BudgetPeriod, periodStart time.Time, pct float64) error {
+	_, err := r.st.DB().ExecContext(ctx, setBudgetNotified, s, m, period, periodStart.UTC(), pct)
 	return err
 }
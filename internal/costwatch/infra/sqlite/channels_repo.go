@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/sqlstore"
+)
+
+var _ port.ChannelsRepo = (*ChannelsRepo)(nil)
+
+type ChannelsRepo struct {
+	st *sqlstore.Store
+}
+
+func NewChannelsRepo(st *sqlstore.Store) *ChannelsRepo {
+	return &ChannelsRepo{st: st}
+}
+
+func scanChannel(row scanner) (port.Channel, error) {
+	var (
+		c      port.Channel
+		config string
+	)
+	if err := row.Scan(&c.Name, &c.Type, &config, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return c, err
+	}
+	c.Config = []byte(config)
+	c.CreatedAt = c.CreatedAt.UTC()
+	c.UpdatedAt = c.UpdatedAt.UTC()
+	return c, nil
+}
+
+//go:embed sql/list_channels.sql
+var listChannelsSQL string
+
+func (r *ChannelsRepo) ListChannels(ctx context.Context) ([]port.Channel, error) {
+	rows, err := r.st.DB().QueryContext(ctx, listChannelsSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []port.Channel
+	for rows.Next() {
+		c, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//go:embed sql/get_channel.sql
+var getChannelSQL string
+
+func (r *ChannelsRepo) GetChannel(ctx context.Context, name string) (port.Channel, bool, error) {
+	c, err := scanChannel(r.st.DB().QueryRowContext(ctx, getChannelSQL, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return port.Channel{}, false, nil
+		}
+		return port.Channel{}, false, err
+	}
+	return c, true, nil
+}
+
+//go:embed sql/upsert_channel.sql
+var upsertChannelSQL string
+
+func (r *ChannelsRepo) UpsertChannel(ctx context.Context, c port.Channel) error {
+	_, err := r.st.DB().ExecContext(ctx, upsertChannelSQL,
+		c.Name, c.Type, string(c.Config), c.CreatedAt.UTC(), c.UpdatedAt.UTC(),
+	)
+	return err
+}
+
+//go:embed sql/delete_channel.sql
+var deleteChannelSQL string
+
+func (r *ChannelsRepo) DeleteChannel(ctx context.Context, name string) error {
+	_, err := r.st.DB().ExecContext(ctx, deleteChannelSQL, name)
+	return err
+}
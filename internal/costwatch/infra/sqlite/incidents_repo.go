@@ -0,0 +1,220 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/sqlstore"
+)
+
+var _ port.IncidentsRepo = (*IncidentsRepo)(nil)
+
+// defaultIncidentLimit caps ListIncidents when the filter sets no limit.
+const defaultIncidentLimit = 100
+
+type IncidentsRepo struct {
+	st *sqlstore.Store
+}
+
+func NewIncidentsRepo(st *sqlstore.Store) *IncidentsRepo {
+	return &IncidentsRepo{st: st}
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanIncident(row scanner) (port.Incident, error) {
+	var (
+		inc      port.Incident
+		resolved sql.NullTime
+		notified sql.NullTime
+		acked    sql.NullTime
+	)
+	err := row.Scan(
+		&inc.ID, &inc.Service, &inc.Metric, &inc.Kind, &inc.Period, &inc.Dimension,
+		&inc.Status, &inc.StartedAt, &inc.UpdatedAt, &resolved,
+		&inc.PeakCost, &inc.Overspend, &inc.Summary, &notified,
+		&acked, &inc.AckedBy, &inc.Severity,
+	)
+	if err != nil {
+		return inc, err
+	}
+	inc.StartedAt = inc.StartedAt.UTC()
+	inc.UpdatedAt = inc.UpdatedAt.UTC()
+	if resolved.Valid {
+		inc.ResolvedAt = resolved.Time.UTC()
+	}
+	if notified.Valid {
+		inc.NotifiedAt = notified.Time.UTC()
+	}
+	if acked.Valid {
+		inc.AckedAt = acked.Time.UTC()
+	}
+	return inc, nil
+}
+
+func (r *IncidentsRepo) queryIncidents(ctx context.Context, query string, args ...any) ([]port.Incident, error) {
+	rows, err := r.st.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []port.Incident
+	for rows.Next() {
+		inc, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// severityOrDefault stores incidents without a severity as warnings.
+func severityOrDefault(s port.Severity) port.Severity {
+	if s == "" {
+		return port.SeverityWarning
+	}
+	return s
+}
+
+//go:embed sql/latest_incident.sql
+var latestIncidentSQL string
+
+func (r *IncidentsRepo) Latest(ctx context.Context, k port.IncidentKey) (port.Incident, bool, error) {
+	row := r.st.DB().QueryRowContext(ctx, latestIncidentSQL, k.Service, k.Metric, k.Kind, k.Period, k.Dimension)
+	inc, err := scanIncident(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return port.Incident{}, false, nil
+		}
+		return port.Incident{}, false, err
+	}
+	return inc, true, nil
+}
+
+//go:embed sql/list_open_incidents.sql
+var listOpenIncidentsSQL string
+
+func (r *IncidentsRepo) ListOpen(ctx context.Context) ([]port.Incident, error) {
+	return r.queryIncidents(ctx, listOpenIncidentsSQL)
+}
+
+//go:embed sql/insert_incident.sql
+var insertIncidentSQL string
+
+func (r *IncidentsRepo) CreateIncident(ctx context.Context, inc port.Incident) (int64, error) {
+	res, err := r.st.DB().ExecContext(ctx, insertIncidentSQL,
+		inc.Service, inc.Metric, inc.Kind, inc.Period, inc.Dimension,
+		inc.Status, inc.StartedAt.UTC(), inc.UpdatedAt.UTC(), nullTime(inc.ResolvedAt),
+		inc.PeakCost, inc.Overspend, inc.Summary, nullTime(inc.NotifiedAt), severityOrDefault(inc.Severity),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+//go:embed sql/update_incident.sql
+var updateIncidentSQL string
+
+func (r *IncidentsRepo) UpdateIncident(ctx context.Context, inc port.Incident) error {
+	_, err := r.st.DB().ExecContext(ctx, updateIncidentSQL,
+		inc.Status, inc.UpdatedAt.UTC(), nullTime(inc.ResolvedAt),
+		inc.PeakCost, inc.Overspend, inc.Summary, nullTime(inc.NotifiedAt), severityOrDefault(inc.Severity),
+		inc.ID,
+	)
+	return err
+}
+
+//go:embed sql/list_incidents.sql
+var listIncidentsSQL string
+
+func (r *IncidentsRepo) ListIncidents(ctx context.Context, f port.IncidentFilter) ([]port.Incident, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultIncidentLimit
+	}
+	from, to := nullTime(f.From), nullTime(f.To)
+	return r.queryIncidents(ctx, listIncidentsSQL,
+		f.Status, f.Status,
+		f.Service, f.Service,
+		f.Metric, f.Metric,
+		from, from,
+		to, to,
+		limit,
+	)
+}
+
+//go:embed sql/list_incidents_between.sql
+var listIncidentsBetweenSQL string
+
+func (r *IncidentsRepo) ListBetween(ctx context.Context, from, to time.Time) ([]port.Incident, error) {
+	return r.queryIncidents(ctx, listIncidentsBetweenSQL, to.UTC(), from.UTC())
+}
+
+//go:embed sql/get_incident.sql
+var getIncidentSQL string
+
+//go:embed sql/list_notifications.sql
+var listNotificationsSQL string
+
+func (r *IncidentsRepo) GetIncident(ctx context.Context, id int64) (port.Incident, bool, error) {
+	inc, err := scanIncident(r.st.DB().QueryRowContext(ctx, getIncidentSQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return port.Incident{}, false, nil
+		}
+		return port.Incident{}, false, err
+	}
+
+	rows, err := r.st.DB().QueryContext(ctx, listNotificationsSQL, id)
+	if err != nil {
+		return port.Incident{}, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n port.Notification
+		if err := rows.Scan(&n.ID, &n.IncidentID, &n.SentAt, &n.Channel, &n.Message, &n.Error); err != nil {
+			return port.Incident{}, false, err
+		}
+		n.SentAt = n.SentAt.UTC()
+		inc.Notifications = append(inc.Notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return port.Incident{}, false, err
+	}
+
+	return inc, true, nil
+}
+
+//go:embed sql/insert_notification.sql
+var insertNotificationSQL string
+
+func (r *IncidentsRepo) AddNotification(ctx context.Context, n port.Notification) error {
+	_, err := r.st.DB().ExecContext(ctx, insertNotificationSQL, n.IncidentID, n.SentAt.UTC(), n.Channel, n.Message, n.Error)
+	return err
+}
+
+//go:embed sql/acknowledge_incident.sql
+var acknowledgeIncidentSQL string
+
+func (r *IncidentsRepo) Acknowledge(ctx context.Context, id int64, by string, at time.Time) error {
+	_, err := r.st.DB().ExecContext(ctx, acknowledgeIncidentSQL, at.UTC(), by, id)
+	return err
+}
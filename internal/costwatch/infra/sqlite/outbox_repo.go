@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/sqlstore"
+)
+
+var _ port.OutboxRepo = (*OutboxRepo)(nil)
+
+// defaultDeliveryLimit caps ListDeliveries when the filter sets no limit.
+const defaultDeliveryLimit = 100
+
+// OutboxRepo keeps deliveries in alert_deliveries, with their event as JSON.
+type OutboxRepo struct {
+	st *sqlstore.Store
+}
+
+func NewOutboxRepo(st *sqlstore.Store) *OutboxRepo {
+	return &OutboxRepo{st: st}
+}
+
+func scanDelivery(row scanner) (port.Delivery, error) {
+	var (
+		d         port.Delivery
+		event     string
+		next      sql.NullTime
+		delivered sql.NullTime
+	)
+	err := row.Scan(
+		&d.ID, &d.IncidentID, &d.Channel, &event, &d.Status, &d.Attempts, &d.LastError,
+		&d.CreatedAt, &next, &delivered,
+	)
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal([]byte(event), &d.Event); err != nil {
+		return d, err
+	}
+	d.CreatedAt = d.CreatedAt.UTC()
+	if next.Valid {
+		d.NextAttemptAt = next.Time.UTC()
+	}
+	if delivered.Valid {
+		d.DeliveredAt = delivered.Time.UTC()
+	}
+	return d, nil
+}
+
+func (r *OutboxRepo) queryDeliveries(ctx context.Context, query string, args ...any) ([]port.Delivery, error) {
+	rows, err := r.st.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []port.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//go:embed sql/enqueue_delivery.sql
+var enqueueDeliverySQL string
+
+func (r *OutboxRepo) Enqueue(ctx context.Context, d port.Delivery) (int64, error) {
+	event, err := json.Marshal(d.Event)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	err = r.st.DB().QueryRowContext(ctx, enqueueDeliverySQL,
+		d.IncidentID, d.Channel, d.Event.DedupKey, string(event), d.CreatedAt.UTC(), nullTime(d.NextAttemptAt),
+	).Scan(&id)
+	return id, err
+}
+
+//go:embed sql/list_due_deliveries.sql
+var listDueDeliveriesSQL string
+
+func (r *OutboxRepo) ListDue(ctx context.Context, now time.Time) ([]port.Delivery, error) {
+	return r.queryDeliveries(ctx, listDueDeliveriesSQL, now.UTC())
+}
+
+//go:embed sql/update_delivery.sql
+var updateDeliverySQL string
+
+func (r *OutboxRepo) UpdateDelivery(ctx context.Context, d port.Delivery) error {
+	_, err := r.st.DB().ExecContext(ctx, updateDeliverySQL,
+		d.Status, d.Attempts, d.LastError, nullTime(d.NextAttemptAt), nullTime(d.DeliveredAt),
+		d.ID,
+	)
+	return err
+}
+
+//go:embed sql/list_deliveries.sql
+var listDeliveriesSQL string
+
+func (r *OutboxRepo) ListDeliveries(ctx context.Context, f port.DeliveryFilter) ([]port.Delivery, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	return r.queryDeliveries(ctx, listDeliveriesSQL, f.Status, f.Status, limit)
+}
@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"time"
+
+	"github.com/tailbits/costwatch/internal/costwatch/port"
+	"github.com/tailbits/costwatch/internal/sqlstore"
+)
+
+var _ port.SilencesRepo = (*SilencesRepo)(nil)
+
+type SilencesRepo struct {
+	st *sqlstore.Store
+}
+
+func NewSilencesRepo(st *sqlstore.Store) *SilencesRepo {
+	return &SilencesRepo{st: st}
+}
+
+func scanSilence(row scanner) (port.Silence, error) {
+	var s port.Silence
+	if err := row.Scan(&s.ID, &s.Service, &s.Metric, &s.StartsAt, &s.EndsAt, &s.Reason, &s.Author, &s.CreatedAt); err != nil {
+		return s, err
+	}
+	s.StartsAt = s.StartsAt.UTC()
+	s.EndsAt = s.EndsAt.UTC()
+	s.CreatedAt = s.CreatedAt.UTC()
+	return s, nil
+}
+
+//go:embed sql/insert_silence.sql
+var insertSilenceSQL string
+
+func (r *SilencesRepo) CreateSilence(ctx context.Context, s port.Silence) (int64, error) {
+	res, err := r.st.DB().ExecContext(ctx, insertSilenceSQL,
+		s.Service, s.Metric, s.StartsAt.UTC(), s.EndsAt.UTC(), s.Reason, s.Author, s.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+//go:embed sql/list_silences.sql
+var listSilencesSQL string
+
+func (r *SilencesRepo) ListSilences(ctx context.Context, from, to time.Time) ([]port.Silence, error) {
+	f, t := nullTime(from), nullTime(to)
+	rows, err := r.st.DB().QueryContext(ctx, listSilencesSQL, f, f, t, t)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []port.Silence
+	for rows.Next() {
+		s, err := scanSilence(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//go:embed sql/get_silence.sql
+var getSilenceSQL string
+
+func (r *SilencesRepo) GetSilence(ctx context.Context, id int64) (port.Silence, bool, error) {
+	s, err := scanSilence(r.st.DB().QueryRowContext(ctx, getSilenceSQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return port.Silence{}, false, nil
+		}
+		return port.Silence{}, false, err
+	}
+	return s, true, nil
+}
+
+//go:embed sql/expire_silence.sql
+var expireSilenceSQL string
+
+func (r *SilencesRepo) ExpireSilence(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.st.DB().ExecContext(ctx, expireSilenceSQL, at.UTC(), id, at.UTC())
+	return err
+}
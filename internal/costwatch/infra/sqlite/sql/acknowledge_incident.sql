@@ -0,0 +1,2 @@
+update alert_incidents set acked_at = ?, acked_by = ?
+where id = ?
@@ -0,0 +1 @@
+delete from notification_channels where name = ?
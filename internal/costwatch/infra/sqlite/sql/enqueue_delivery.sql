@@ -0,0 +1,6 @@
+insert into alert_deliveries(incident_id, channel, dedup_key, event, status, created_at, next_attempt_at)
+values(?, ?, ?, ?, 'pending', ?, ?)
+on conflict (channel, dedup_key) where status = 'pending' do update set
+  incident_id = excluded.incident_id,
+  event = excluded.event
+returning id
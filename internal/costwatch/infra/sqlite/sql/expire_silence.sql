@@ -0,0 +1,2 @@
+update silences set ends_at = ?
+where id = ? and ends_at > ?
@@ -0,0 +1,2 @@
+select notified_pct from budget_state
+where service = ? and metric = ? and period = ? and period_start = ?
@@ -0,0 +1,3 @@
+select name, type, config, created_at, updated_at
+from notification_channels
+where name = ?
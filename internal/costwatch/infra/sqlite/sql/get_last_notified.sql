@@ -1 +1,2 @@
-select last_notified from sync_state where service=? and metric=?
+select last_notified from alert_last_notified
+where service=? and metric=? and kind=? and period=? and dimension=?
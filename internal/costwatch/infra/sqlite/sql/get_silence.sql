@@ -0,0 +1,3 @@
+select id, service, metric, starts_at, ends_at, reason, author, created_at
+from silences
+where id = ?
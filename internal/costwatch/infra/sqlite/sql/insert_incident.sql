@@ -0,0 +1,2 @@
+insert into alert_incidents(service, metric, kind, period, dimension, status, started_at, updated_at, resolved_at, peak_cost, overspend, summary, notified_at, severity)
+values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -0,0 +1,2 @@
+insert into alert_notifications(incident_id, sent_at, channel, message, error)
+values(?, ?, ?, ?, ?)
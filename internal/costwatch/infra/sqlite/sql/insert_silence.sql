@@ -0,0 +1,2 @@
+insert into silences(service, metric, starts_at, ends_at, reason, author, created_at)
+values(?, ?, ?, ?, ?, ?, ?)
@@ -0,0 +1,5 @@
+select id, service, metric, kind, period, dimension, status, started_at, updated_at, resolved_at, peak_cost, overspend, summary, notified_at, acked_at, acked_by, severity
+from alert_incidents
+where service = ? and metric = ? and kind = ? and period = ? and dimension = ?
+order by id desc
+limit 1
@@ -1 +1 @@
-SELECT service, metric, threshold FROM alert_rules
+SELECT service, metric, kind, period, threshold, amount, percentages, basis, method, sensitivity, baseline_weeks, percent, percentile, baseline_days, renotify_interval, critical, escalate_after, recipients, channels FROM alert_rules
@@ -0,0 +1,5 @@
+select id, incident_id, channel, event, status, attempts, last_error, created_at, next_attempt_at, delivered_at
+from alert_deliveries
+where (? = '' and status != 'delivered' or status = ?)
+order by created_at desc, id desc
+limit ?
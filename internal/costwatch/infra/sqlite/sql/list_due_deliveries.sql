@@ -0,0 +1,4 @@
+select id, incident_id, channel, event, status, attempts, last_error, created_at, next_attempt_at, delivered_at
+from alert_deliveries
+where status = 'pending' and next_attempt_at <= ?
+order by id
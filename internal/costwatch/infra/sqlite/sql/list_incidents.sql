@@ -0,0 +1,9 @@
+select id, service, metric, kind, period, dimension, status, started_at, updated_at, resolved_at, peak_cost, overspend, summary, notified_at, acked_at, acked_by, severity
+from alert_incidents
+where (? = '' or status = ?)
+  and (? = '' or service = ?)
+  and (? = '' or metric = ?)
+  and (? is null or started_at >= ?)
+  and (? is null or started_at < ?)
+order by started_at desc, id desc
+limit ?
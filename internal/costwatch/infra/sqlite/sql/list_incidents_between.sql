@@ -0,0 +1,5 @@
+select id, service, metric, kind, period, dimension, status, started_at, updated_at, resolved_at, peak_cost, overspend, summary, notified_at, acked_at, acked_by, severity
+from alert_incidents
+where started_at < ?
+  and (resolved_at is null or resolved_at > ?)
+order by started_at, id
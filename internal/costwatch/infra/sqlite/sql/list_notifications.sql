@@ -0,0 +1,4 @@
+select id, incident_id, sent_at, channel, message, error
+from alert_notifications
+where incident_id = ?
+order by sent_at, id
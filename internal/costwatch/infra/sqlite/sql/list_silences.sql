@@ -0,0 +1,5 @@
+select id, service, metric, starts_at, ends_at, reason, author, created_at
+from silences
+where (? is null or ends_at > ?)
+  and (? is null or starts_at <= ?)
+order by created_at desc, id desc
@@ -0,0 +1,5 @@
+insert into budget_state(service, metric, period, period_start, notified_pct)
+values(?, ?, ?, ?, ?)
+on conflict(service, metric, period) do update set
+  period_start=excluded.period_start,
+  notified_pct=excluded.notified_pct
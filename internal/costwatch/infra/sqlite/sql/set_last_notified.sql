@@ -1,2 +1,4 @@
-update sync_state set last_notified = ?
-where service = ? and metric = ?
+insert into alert_last_notified(service, metric, kind, period, dimension, last_notified)
+values(?, ?, ?, ?, ?, ?)
+on conflict(service, metric, kind, period, dimension) do update set
+  last_notified=excluded.last_notified
@@ -0,0 +1,7 @@
+update alert_deliveries set
+  status = ?,
+  attempts = ?,
+  last_error = ?,
+  next_attempt_at = ?,
+  delivered_at = ?
+where id = ?
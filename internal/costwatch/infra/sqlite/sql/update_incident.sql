@@ -0,0 +1,10 @@
+update alert_incidents set
+  status = ?,
+  updated_at = ?,
+  resolved_at = ?,
+  peak_cost = ?,
+  overspend = ?,
+  summary = ?,
+  notified_at = ?,
+  severity = ?
+where id = ?
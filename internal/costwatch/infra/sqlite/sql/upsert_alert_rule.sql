@@ -1,3 +1,18 @@
-insert into alert_rules(service, metric, threshold)
-values(?, ?, ?)
-on conflict(service, metric) do update set threshold=excluded.threshold
+insert into alert_rules(service, metric, kind, period, threshold, amount, percentages, basis, method, sensitivity, baseline_weeks, percent, percentile, baseline_days, renotify_interval, critical, escalate_after, recipients, channels)
+values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+on conflict(service, metric, kind, period) do update set
+  threshold=excluded.threshold,
+  amount=excluded.amount,
+  percentages=excluded.percentages,
+  basis=excluded.basis,
+  method=excluded.method,
+  sensitivity=excluded.sensitivity,
+  baseline_weeks=excluded.baseline_weeks,
+  percent=excluded.percent,
+  percentile=excluded.percentile,
+  baseline_days=excluded.baseline_days,
+  renotify_interval=excluded.renotify_interval,
+  critical=excluded.critical,
+  escalate_after=excluded.escalate_after,
+  recipients=excluded.recipients,
+  channels=excluded.channels
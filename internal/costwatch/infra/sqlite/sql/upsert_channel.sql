@@ -0,0 +1,6 @@
+insert into notification_channels(name, type, config, created_at, updated_at)
+values(?, ?, ?, ?, ?)
+on conflict(name) do update set
+  type=excluded.type,
+  config=excluded.config,
+  updated_at=excluded.updated_at
@@ -1,18 +1,183 @@
 package port
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
-// AlertRule defines a per-hour threshold for a service/metric.
+// RuleKind selects how an alert rule is evaluated.
+type RuleKind string
+
+const (
+	// RuleThreshold fires when the cost of an hour exceeds Threshold.
+	RuleThreshold RuleKind = "threshold"
+	// RuleBudget fires when the spend of a Period crosses a percentage of Amount.
+	RuleBudget RuleKind = "budget"
+	// RuleAnomaly fires when a bucket is far above the same time of the week in
+	// the past BaselineWeeks weeks.
+	RuleAnomaly RuleKind = "anomaly"
+	// RuleBaseline fires when the cost of an hour is more than Percent above the
+	// Percentile of the hourly costs over the BaselineDays days before.
+	RuleBaseline RuleKind = "baseline"
+	// RuleChange fires when the cost of an hour is more than Percent above the
+	// cost of the hour before.
+	RuleChange RuleKind = "change"
+)
+
+// Percentile selects the statistic of a baseline rule.
+type Percentile string
+
+const (
+	P50  Percentile = "p50"
+	P90  Percentile = "p90"
+	P95  Percentile = "p95"
+	PMax Percentile = "max"
+)
+
+// AnomalyMethod selects how far a bucket is from its baseline.
+type AnomalyMethod string
+
+const (
+	// MethodZScore measures the distance from the mean in standard deviations.
+	MethodZScore AnomalyMethod = "zscore"
+	// MethodMAD measures the distance from the median in (scaled) median
+	// absolute deviations, which is less affected by past outliers.
+	MethodMAD AnomalyMethod = "mad"
+)
+
+// BudgetPeriod is the (UTC) calendar period a budget applies to.
+type BudgetPeriod string
+
+const (
+	PeriodDaily   BudgetPeriod = "daily"
+	PeriodWeekly  BudgetPeriod = "weekly" // ISO weeks, starting on Monday
+	PeriodMonthly BudgetPeriod = "monthly"
+)
+
+// BudgetBasis selects the spend compared against a budget.
+type BudgetBasis string
+
+const (
+	// BasisActual compares the spend of the period so far.
+	BasisActual BudgetBasis = "actual"
+	// BasisForecast compares the spend projected for the end of the period.
+	BasisForecast BudgetBasis = "forecast"
+)
+
+// DefaultBudgetPercentages are the budget percentages notified when a rule sets none.
+var DefaultBudgetPercentages = []float64{50, 80, 100}
+
+// Defaults of anomaly rules.
+const (
+	DefaultSensitivity   = 3.0
+	DefaultBaselineWeeks = 4
+)
+
+// Defaults of baseline rules.
+const (
+	DefaultPercentile   = P90
+	DefaultBaselineDays = 7
+)
+
+// Severity rates how urgent an alert is.
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// MaxSeverity returns the more severe of a and b. The empty severity ranks
+// below warning.
+func MaxSeverity(a, b Severity) Severity {
+	if a == SeverityCritical || b == SeverityCritical {
+		return SeverityCritical
+	}
+	if a == SeverityWarning || b == SeverityWarning {
+		return SeverityWarning
+	}
+	return ""
+}
+
+// DefaultRenotifyInterval is used by rules that set no RenotifyInterval.
+const DefaultRenotifyInterval = time.Hour
+
+// AlertRule defines an alert for a service/metric: a per-hour threshold, a
+// budget for a period, anomaly detection, or a limit relative to a trailing
+// baseline or to the previous hour. Rules are keyed by service, metric, kind
+// and period.
 type AlertRule struct {
 	Service   string
 	Metric    string
+	Kind      RuleKind
 	Threshold float64
+
+	// Budget rules only.
+	Period      BudgetPeriod
+	Amount      float64
+	Percentages []float64
+	Basis       BudgetBasis
+
+	// Anomaly rules only. Sensitivity is the score above which a bucket is
+	// anomalous: lower values fire more often.
+	Method        AnomalyMethod
+	Sensitivity   float64
+	BaselineWeeks int
+
+	// Baseline and change rules only. Percent is how far above the baseline
+	// (or previous hour) the cost may go: 100 fires once it doubles.
+	Percent      float64
+	Percentile   Percentile
+	BaselineDays int
+
+	// RenotifyInterval is how often an open window of the rule is notified
+	// again until it is acknowledged. Budget rules ignore it.
+	RenotifyInterval time.Duration
+
+	// Critical is the level above which a breach is critical rather than a
+	// warning, in the unit of the rule's warning level: an hourly cost for
+	// threshold rules, a score for anomaly rules, a percent for baseline and
+	// change rules, and a budget percentage for budget rules. Zero disables it.
+	Critical float64
+	// EscalateAfter escalates a window that stays open that long to critical.
+	// Zero disables escalation; budget rules ignore it.
+	EscalateAfter time.Duration
+
+	// Recipients are the email addresses the email notifier sends the alerts
+	// of the rule to, besides its default recipients.
+	Recipients []string
+
+	// Channels are the names of the notification channels the alerts of the
+	// rule go to, instead of the default notifier.
+	Channels []string
+}
+
+// Bounds returns the period containing t as [start, end).
+func (p BudgetPeriod) Bounds(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case PeriodWeekly:
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	case PeriodMonthly:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
 }
 
 // AlertRuleRepo stores and retrieves alert rules.
 type AlertsRepo interface {
 	ListRules(ctx context.Context) ([]AlertRule, error)
 	UpsertRule(ctx context.Context, r AlertRule) error
-	GetLastNotified(ctx context.Context, service, metric string) (int64, bool, error)
-	SetLastNotified(ctx context.Context, service, metric string, timeUnix int64) error
+	// GetLastNotified returns when the rule and dimension value of k were last
+	// notified, if ever.
+	GetLastNotified(ctx context.Context, k IncidentKey) (int64, bool, error)
+	SetLastNotified(ctx context.Context, k IncidentKey, timeUnix int64) error
+	// GetBudgetNotified returns the highest budget percentage notified for the
+	// period starting at periodStart, or 0 if none was.
+	GetBudgetNotified(ctx context.Context, service, metric string, period BudgetPeriod, periodStart time.Time) (float64, error)
+	SetBudgetNotified(ctx context.Context, service, metric string, period BudgetPeriod, periodStart time.Time, pct float64) error
 }
@@ -1,5 +1,36 @@
 package port
 
+import "time"
+
 type Catalog interface {
-	ComputeCost(service, metric string, units float64) (float64, bool)
+	// ComputeCost prices units at the price effective at the given time, as if
+	// nothing had been consumed yet in the billing month.
+	ComputeCost(service, metric string, at time.Time, units float64) (float64, bool)
+	// ComputeMarginalCost prices units at the price effective at the given time,
+	// given monthToDate units already consumed in the same billing month, so free
+	// allowances and tiers apply at the right rate.
+	ComputeMarginalCost(service, metric string, at time.Time, monthToDate, units float64) (float64, bool)
+	// PriceVersions lists every known price, oldest first per service/metric.
+	PriceVersions() []PriceVersion
+}
+
+// PriceVersion is the price of a service/metric over an effective date range.
+// A zero EffectiveFrom means the price has always applied; a zero EffectiveTo
+// means it still applies.
+type PriceVersion struct {
+	Service       string
+	Metric        string
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time
+	Price         float64
+	UnitSize      float64
+	Currency      string
+	FreeUnits     float64
+	Tiers         []PriceTier
+}
+
+// PriceTier prices the month-to-date units below UpTo (and above the previous tier).
+type PriceTier struct {
+	UpTo  float64
+	Price float64
 }
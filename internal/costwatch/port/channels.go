@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// ChannelType is the kind of notifier behind a channel.
+type ChannelType string
+
+const (
+	ChannelWebhook   ChannelType = "webhook"
+	ChannelPagerDuty ChannelType = "pagerduty"
+	ChannelEmail     ChannelType = "email"
+)
+
+// Channel is a named destination of notifications, e.g. the Slack channel of
+// a team, that rules route their alerts to. Config is the JSON configuration
+// of its notifier, which depends on its Type.
+type Channel struct {
+	Name      string
+	Type      ChannelType
+	Config    json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ChannelsRepo stores notification channels.
+type ChannelsRepo interface {
+	// ListChannels returns every channel, by name.
+	ListChannels(ctx context.Context) ([]Channel, error)
+	GetChannel(ctx context.Context, name string) (Channel, bool, error)
+	// UpsertChannel creates a channel, or replaces the type and config of the
+	// channel with the same name.
+	UpsertChannel(ctx context.Context, c Channel) error
+	DeleteChannel(ctx context.Context, name string) error
+}
@@ -0,0 +1,82 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// IncidentStatus is the lifecycle state of an incident.
+type IncidentStatus string
+
+const (
+	IncidentOpen     IncidentStatus = "open"
+	IncidentResolved IncidentStatus = "resolved"
+)
+
+// IncidentKey identifies the rule (and dimension value) an incident is for.
+type IncidentKey struct {
+	Service   string
+	Metric    string
+	Kind      RuleKind
+	Period    BudgetPeriod
+	Dimension string
+}
+
+// Incident records a rule firing, from the first breach until it resolved.
+// PeakCost is the highest hourly cost (or period spend, for budgets) seen, and
+// Overspend the cost above the rule's baseline or budget.
+type Incident struct {
+	ID int64
+	IncidentKey
+	Status     IncidentStatus
+	Severity   Severity // highest severity seen, including escalation
+	StartedAt  time.Time
+	UpdatedAt  time.Time
+	ResolvedAt time.Time // zero while open
+	PeakCost   float64
+	Overspend  float64
+	Summary    string    // latest notification text
+	NotifiedAt time.Time // last delivered notification; zero if none
+	AckedAt    time.Time // zero until acknowledged
+	AckedBy    string
+
+	Notifications []Notification // set by GetIncident only
+}
+
+// Notification is a notification sent (or attempted) for an incident. Error
+// is empty when it was delivered.
+type Notification struct {
+	ID         int64
+	IncidentID int64
+	SentAt     time.Time
+	Channel    string
+	Message    string
+	Error      string
+}
+
+// IncidentFilter narrows down ListIncidents. Zero fields match everything.
+type IncidentFilter struct {
+	Status  IncidentStatus
+	Service string
+	Metric  string
+	From    time.Time // started at or after
+	To      time.Time // started before
+	Limit   int
+}
+
+// IncidentsRepo stores alert incidents and their notifications.
+type IncidentsRepo interface {
+	// Latest returns the most recent incident for key, open or resolved.
+	Latest(ctx context.Context, key IncidentKey) (Incident, bool, error)
+	// ListOpen returns every open incident.
+	ListOpen(ctx context.Context) ([]Incident, error)
+	CreateIncident(ctx context.Context, inc Incident) (int64, error)
+	UpdateIncident(ctx context.Context, inc Incident) error
+	ListIncidents(ctx context.Context, f IncidentFilter) ([]Incident, error)
+	// ListBetween returns the incidents open at some time in [from, to).
+	ListBetween(ctx context.Context, from, to time.Time) ([]Incident, error)
+	GetIncident(ctx context.Context, id int64) (Incident, bool, error)
+	AddNotification(ctx context.Context, n Notification) error
+	// Acknowledge records who acknowledged an incident, and when.
+	Acknowledge(ctx context.Context, id int64, by string, at time.Time) error
+}
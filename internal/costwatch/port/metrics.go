@@ -7,9 +7,21 @@ import (
 
 // MetricBucket is an aggregated data point for a service/metric at a specific bucket timestamp.
 type MetricBucket struct {
+	Service string
+	Metric  string
+	// Dimension is the value of the MetricsQuery.GroupBy dimension for this bucket.
+	// It is empty when no grouping was requested or the datapoints lack the dimension.
+	Dimension string
+	Timestamp time.Time
+	Units     float64
+}
+
+// MetricTotal is the sum of units for a service/metric (and dimension value)
+// over a time range.
+type MetricTotal struct {
 	Service   string
 	Metric    string
-	Timestamp time.Time
+	Dimension string // value of the GroupBy dimension, if any
 	Units     float64
 }
 
@@ -17,14 +29,47 @@ type MetricBucket struct {
 type MetricPercentiles struct {
 	Service string
 	Metric  string
-	P50     float64
-	P90     float64
-	P95     float64
-	PMax    float64
+	// Dimension is the value of the MetricsQuery.GroupBy dimension, if any.
+	Dimension string
+	P50       float64
+	P90       float64
+	P95       float64
+	PMax      float64
+}
+
+// MetricBaseline summarizes the bucket units of a service/metric (and
+// dimension) at the same position in the week over past weeks. Slot is the
+// bucket start in seconds since the beginning of the (UTC, epoch-aligned) week.
+type MetricBaseline struct {
+	Service   string
+	Metric    string
+	Dimension string
+	Slot      int64
+	Samples   int
+	Mean      float64
+	StdDev    float64
+	Median    float64
+	MAD       float64 // median absolute deviation from Median
+}
+
+// MetricsQuery narrows down and groups the aggregated metrics.
+type MetricsQuery struct {
+	// GroupBy breaks buckets down by the value of the named dimension (e.g. LogGroupName).
+	// The reserved names "account" and "region" group by the AWS account and region.
+	GroupBy string
+	// Service and Metric, when set, keep only the datapoints of that service/metric.
+	Service string
+	Metric  string
+	// Account and Region, when set, keep only the datapoints collected from them.
+	Account string
+	Region  string
 }
 
 // MetricsQueryPort defines access to aggregated metrics storage (e.g., ClickHouse).
 type MetricsRepo interface {
-	Aggregate(ctx context.Context, start, end time.Time, bucket time.Duration) ([]MetricBucket, error)
-	Percentiles(ctx context.Context, start, end time.Time, bucket time.Duration) ([]MetricPercentiles, error)
+	Aggregate(ctx context.Context, start, end time.Time, bucket time.Duration, q MetricsQuery) ([]MetricBucket, error)
+	Percentiles(ctx context.Context, start, end time.Time, bucket time.Duration, q MetricsQuery) ([]MetricPercentiles, error)
+	Totals(ctx context.Context, start, end time.Time, q MetricsQuery) ([]MetricTotal, error)
+	// Baseline summarizes the buckets between start and end per week slot.
+	Baseline(ctx context.Context, start, end time.Time, bucket time.Duration, q MetricsQuery) ([]MetricBaseline, error)
 }
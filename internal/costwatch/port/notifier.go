@@ -1,8 +1,188 @@
 package port
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
-// Notifier delivers alert messages.
+// Notifier delivers alerts, each channel rendering them in its own format.
 type Notifier interface {
-	Send(ctx context.Context, text string) error
+	Send(ctx context.Context, ev AlertEvent) error
+}
+
+// AlertTracker is a Notifier that opens an alert on the first event of a
+// DedupKey, updates it on the next ones and closes it on a resolved event.
+// Silences do not hold back the resolved events of alert trackers, which would
+// otherwise keep their alerts open.
+type AlertTracker interface {
+	Notifier
+	TracksAlerts() bool
+}
+
+// ConfigurableNotifier is a Notifier that drops alerts until it is
+// configured, such as a webhook without URL.
+type ConfigurableNotifier interface {
+	Notifier
+	Configured() bool
+}
+
+// ResponseError is the error of a notifier whose endpoint answered with a
+// non-2xx status, and Body the start of the answer.
+type ResponseError struct {
+	Notifier   string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("%s: %s: %s", e.Notifier, e.Status, e.Body)
+	}
+	return fmt.Sprintf("%s: %s", e.Notifier, e.Status)
+}
+
+// AlertState tells whether an alert is firing or resolved.
+type AlertState string
+
+const (
+	AlertFiring   AlertState = "firing"
+	AlertResolved AlertState = "resolved"
+)
+
+// AlertEvent is a notification of an alert of Rule, either a window of hours
+// that breached the rule or a budget that crossed one of its percentages.
+// DedupKey is the same for every event of an alert, from its first
+// notification until it resolves.
+//
+// Start and End bound the window (or budget period); End is zero while a
+// window is ongoing. Expected and Actual are the expected and actual cost of
+// the window (a budget's amount and spend), Baseline describes what the rule
+// compared the hours against, and Costs are the hourly costs of the window.
+// Percent is the percentage of its amount a budget reached. Escalated reports
+// that the window turned critical by staying open for Rule.EscalateAfter.
+// Resolved events report the Overspend and PeakCost of the whole alert.
+type AlertEvent struct {
+	DedupKey string
+	State    AlertState
+	Severity Severity
+	Rule     AlertRule
+
+	Service   string
+	Metric    string
+	Dimension string
+	Kind      RuleKind
+	Baseline  string
+	Start     time.Time
+	End       time.Time
+	Hours     int
+	Expected  float64
+	Actual    float64
+	Costs     []float64
+	Percent   float64
+	Escalated bool
+
+	Overspend float64
+	PeakCost  float64
+}
+
+func (e AlertEvent) Resolved() bool { return e.State == AlertResolved }
+
+// Scope names the service/metric of an alert, e.g. "aws.s3/storage_gb", or
+// "total" for a budget of all services.
+func (e AlertEvent) Scope() string {
+	var scope string
+	switch {
+	case e.Service == "" && e.Metric == "":
+		scope = "total"
+	case e.Metric == "":
+		scope = e.Service
+	case e.Service == "":
+		scope = e.Metric
+	default:
+		scope = e.Service + "/" + e.Metric
+	}
+	if e.Dimension != "" {
+		scope += " (" + e.Dimension + ")"
+	}
+	return scope
+}
+
+// Title summarizes an alert in a line, e.g.
+// "[CostWatch] Critical alert: aws.s3/storage_gb".
+func (e AlertEvent) Title() string {
+	return e.prefix() + " " + e.Scope()
+}
+
+func (e AlertEvent) prefix() string {
+	switch {
+	case e.Resolved():
+		return "[CostWatch] Resolved:"
+	case e.Kind == RuleBudget && e.Severity == SeverityCritical:
+		return "[CostWatch] Critical budget:"
+	case e.Kind == RuleBudget:
+		return "[CostWatch] Budget:"
+	case e.Severity == SeverityCritical:
+		return "[CostWatch] Critical alert:"
+	}
+	return "[CostWatch] Alert:"
+}
+
+// Window describes the window of an alert.
+func (e AlertEvent) Window() string {
+	if e.End.IsZero() {
+		return "since " + e.Start.Format(time.RFC3339) + " UTC (ongoing)"
+	}
+	return e.Start.Format(time.RFC3339) + " to " + e.End.Format(time.RFC3339) + " UTC"
+}
+
+// Summary describes an alert in a sentence, the default text of notifications.
+func (e AlertEvent) Summary() string {
+	if e.Resolved() {
+		return fmt.Sprintf("%s %s %s alert is over after %s ($%.2f above expected, peak $%.2f/h) from %s to %s UTC",
+			e.prefix(), e.Scope(), e.Kind, FormatDuration(e.End.Sub(e.Start)), e.Overspend, e.PeakCost,
+			e.Start.Format(time.RFC3339), e.End.Format(time.RFC3339))
+	}
+
+	prefix := e.prefix()
+	if e.Kind == RuleBudget {
+		if e.Rule.Basis == BasisForecast {
+			return fmt.Sprintf("%s %s %s spend is forecast to reach %.0f%% of $%.2f ($%.2f forecast) for the period starting %s UTC", prefix, e.Scope(), e.Rule.Period, e.Percent, e.Expected, e.Actual, e.Start.Format(time.DateOnly))
+		}
+		return fmt.Sprintf("%s %s %s spend reached %.0f%% of $%.2f ($%.2f spent) for the period starting %s UTC", prefix, e.Scope(), e.Rule.Period, e.Percent, e.Expected, e.Actual, e.Start.Format(time.DateOnly))
+	}
+
+	var what string
+	switch e.Kind {
+	case RuleAnomaly:
+		what = "was anomalously high"
+	case RuleBaseline, RuleChange:
+		what = "exceeded its baseline (" + e.Baseline + ")"
+	default:
+		what = "exceeded threshold"
+	}
+	var text string
+	if e.End.IsZero() {
+		text = fmt.Sprintf("%s %s %s for %dh (expected $%.2f, actual $%.2f) since %s UTC (ongoing)", prefix, e.Scope(), what, e.Hours, e.Expected, e.Actual, e.Start.Format(time.RFC3339))
+	} else {
+		text = fmt.Sprintf("%s %s %s for %dh (expected $%.2f, actual $%.2f) from %s to %s UTC", prefix, e.Scope(), what, e.Hours, e.Expected, e.Actual, e.Start.Format(time.RFC3339), e.End.Format(time.RFC3339))
+	}
+	if e.Escalated {
+		text += " (escalated after " + FormatDuration(e.Rule.EscalateAfter) + ")"
+	}
+	return text
+}
+
+// FormatDuration formats d in hours and minutes, e.g. "3h" or "1h25m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%dm", h, m)
 }
@@ -0,0 +1,51 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// DeliveryStatus is the state of a delivery in the outbox.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed" // gave up retrying
+)
+
+// Delivery is a notification of an alert to a channel, kept in the outbox
+// until it is delivered. A pending delivery is attempted at NextAttemptAt, and
+// again later after every failure, until it is delivered or failed for good.
+type Delivery struct {
+	ID            int64
+	IncidentID    int64 // zero when incidents are not tracked
+	Channel       string
+	Event         AlertEvent
+	Status        DeliveryStatus
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time // zero once delivered or failed
+	DeliveredAt   time.Time // zero until delivered
+}
+
+// DeliveryFilter narrows down ListDeliveries. An empty Status matches the
+// pending and failed deliveries.
+type DeliveryFilter struct {
+	Status DeliveryStatus
+	Limit  int
+}
+
+// OutboxRepo stores the notifications of alerts until they are delivered.
+type OutboxRepo interface {
+	// Enqueue adds a pending delivery. While a delivery of the same alert
+	// (Event.DedupKey) to the same channel is pending, its event is replaced
+	// instead, so that only the latest state of an alert is delivered.
+	Enqueue(ctx context.Context, d Delivery) (int64, error)
+	// ListDue returns the pending deliveries to attempt by now, oldest first.
+	ListDue(ctx context.Context, now time.Time) ([]Delivery, error)
+	UpdateDelivery(ctx context.Context, d Delivery) error
+	// ListDeliveries returns deliveries, most recently created first.
+	ListDeliveries(ctx context.Context, f DeliveryFilter) ([]Delivery, error)
+}
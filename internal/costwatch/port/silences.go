@@ -0,0 +1,45 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// Silence mutes the notifications of a service/metric from StartsAt until
+// EndsAt. An empty Service or Metric matches any.
+type Silence struct {
+	ID        int64
+	Service   string
+	Metric    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	Author    string
+	CreatedAt time.Time
+}
+
+// Matches reports whether the silence applies to service/metric.
+func (s Silence) Matches(service, metric string) bool {
+	return (s.Service == "" || s.Service == service) && (s.Metric == "" || s.Metric == metric)
+}
+
+// Overlaps reports whether the silence is in effect at some time in [start, end).
+func (s Silence) Overlaps(start, end time.Time) bool {
+	return s.StartsAt.Before(end) && s.EndsAt.After(start)
+}
+
+// ActiveAt reports whether the silence is in effect at t.
+func (s Silence) ActiveAt(t time.Time) bool {
+	return !s.StartsAt.After(t) && s.EndsAt.After(t)
+}
+
+// SilencesRepo stores silences.
+type SilencesRepo interface {
+	CreateSilence(ctx context.Context, s Silence) (int64, error)
+	// ListSilences returns the silences in effect at some time between from and
+	// to, most recently created first. A zero bound is unbounded.
+	ListSilences(ctx context.Context, from, to time.Time) ([]Silence, error)
+	GetSilence(ctx context.Context, id int64) (Silence, bool, error)
+	// ExpireSilence ends a silence at the given time, unless it already ended.
+	ExpireSilence(ctx context.Context, id int64, at time.Time) error
+}
@@ -2,6 +2,8 @@ package costwatch
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -15,6 +17,34 @@ type Metric interface {
 type Datapoint struct {
 	Value     float64
 	Timestamp time.Time
+	// Dimensions identifies the slice of the metric this datapoint belongs to,
+	// e.g. {"LogGroupName": "/aws/lambda/api"}. Empty for account-wide values.
+	Dimensions map[string]string
+	// Account and Region identify where the datapoint was collected, when the
+	// provider collects from several cloud accounts or regions.
+	Account string
+	Region  string
+}
+
+// DimensionsKey returns a stable string representation of the datapoint
+// dimensions, suitable for use in storage keys.
+func (d Datapoint) DimensionsKey() string {
+	if len(d.Dimensions) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(d.Dimensions))
+	for k := range d.Dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+d.Dimensions[k])
+	}
+
+	return strings.Join(parts, ",")
 }
 
 type Service interface {
@@ -22,3 +52,12 @@ type Service interface {
 	Metrics() []Metric
 	NewMetric(mtr Metric)
 }
+
+// BatchService is implemented by services that can fetch the datapoints of
+// several of their metrics in one go. The result is keyed by metric label.
+// When only some of its sources fail, BatchDatapoints returns the datapoints
+// of the others along with the error.
+type BatchService interface {
+	Service
+	BatchDatapoints(ctx context.Context, metrics []Metric, start time.Time, end time.Time) (map[string][]Datapoint, error)
+}
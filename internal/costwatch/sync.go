@@ -11,6 +11,7 @@ import (
 // - End is always "now" (UTC).
 // - Start is the oldest (earliest) of [last-synced, now-15m] to ensure we fetch at least 15 minutes.
 // - If no last-synced exists, default to a first-time lookback (currently 7 days) to backfill history.
+// Metrics of a BatchService that share the same window are fetched together.
 func (cw *CostWatch) Sync(ctx context.Context) error {
 	st, err := cw.getSyncStore()
 	if err != nil {
@@ -19,6 +20,9 @@ func (cw *CostWatch) Sync(ctx context.Context) error {
 	now := time.Now().UTC()
 	fifteenAgo := now.Add(-15 * time.Minute)
 	for _, s := range ListServices() {
+		// Group metrics by sync window start, preserving registration order.
+		var starts []time.Time
+		byStart := make(map[int64][]Metric) // key: start in unix nanoseconds
 		for _, m := range s.Metrics() {
 			last, ok, err := st.GetLastSync(ctx, s.Label(), m.Label())
 			if err != nil {
@@ -31,17 +35,40 @@ func (cw *CostWatch) Sync(ctx context.Context) error {
 			} else if last.After(fifteenAgo) {
 				start = fifteenAgo
 			}
-			end := now
-			if !start.Before(end) {
+			if !start.Before(now) {
 				continue
 			}
-			cw.log.Info("fetching metric", "service", s.Label(), "metric", m.Label(), "start", start, "end", end)
-			if err := cw.FetchMetricForService(ctx, s, m, start, end); err != nil {
-				cw.log.Error("FetchMetricForService failed", "service", s.Label(), "metric", m.Label(), "error", err)
-				continue
+			if _, seen := byStart[start.UnixNano()]; !seen {
+				starts = append(starts, start)
+			}
+			byStart[start.UnixNano()] = append(byStart[start.UnixNano()], m)
+		}
+
+		end := now
+		for _, start := range starts {
+			ms := byStart[start.UnixNano()]
+			if bs, ok := s.(BatchService); ok {
+				cw.log.Info("fetching metrics", "service", s.Label(), "metrics", len(ms), "start", start, "end", end)
+				if err := cw.FetchMetricsForService(ctx, bs, ms, start, end); err != nil {
+					cw.log.Error("FetchMetricsForService failed", "service", s.Label(), "error", err)
+					continue
+				}
+			} else {
+				fetched := ms[:0:0]
+				for _, m := range ms {
+					cw.log.Info("fetching metric", "service", s.Label(), "metric", m.Label(), "start", start, "end", end)
+					if err := cw.FetchMetricForService(ctx, s, m, start, end); err != nil {
+						cw.log.Error("FetchMetricForService failed", "service", s.Label(), "metric", m.Label(), "error", err)
+						continue
+					}
+					fetched = append(fetched, m)
+				}
+				ms = fetched
 			}
-			if err := st.SetLastSync(ctx, s.Label(), m.Label(), end); err != nil {
-				cw.log.Error("syncstate.SetLastSync error", "service", s.Label(), "metric", m.Label(), "error", err)
+			for _, m := range ms {
+				if err := st.SetLastSync(ctx, s.Label(), m.Label(), end); err != nil {
+					cw.log.Error("syncstate.SetLastSync error", "service", s.Label(), "metric", m.Label(), "error", err)
+				}
 			}
 		}
 	}
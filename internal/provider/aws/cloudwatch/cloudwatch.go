@@ -1,18 +1,40 @@
 package cloudwatch
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/tailbits/costwatch/internal/costwatch"
 )
 
-var _ costwatch.Service = (*Service)(nil)
+var _ costwatch.BatchService = (*Service)(nil)
+
+// QueryMetric is a costwatch.Metric backed by CloudWatch series. The Service
+// fetches the series of all its QueryMetrics with shared GetMetricData requests,
+// once per target; client lists the series available in that target.
+type QueryMetric interface {
+	costwatch.Metric
+	Queries(ctx context.Context, client *cloudwatch.Client) ([]Query, error)
+}
 
 type Service struct {
-	mtrcs []costwatch.Metric
+	mtrcs   []costwatch.Metric
+	targets []Target
 }
 
-func NewService(cfg aws.Config) *Service {
-	return &Service{}
+// NewService creates the CloudWatch service collecting from the given targets.
+// Without targets, it collects from the account and region of cfg.
+func NewService(cfg aws.Config, targets ...Target) *Service {
+	if len(targets) == 0 {
+		targets = []Target{{Region: cfg.Region, Client: cloudwatch.NewFromConfig(cfg)}}
+	}
+	return &Service{
+		targets: targets,
+	}
 }
 
 // =============================================================================
@@ -27,3 +49,73 @@ func (s *Service) Label() string {
 func (s *Service) Metrics() []costwatch.Metric {
 	return s.mtrcs
 }
+
+// BatchDatapoints implements costwatch.BatchService. The queries of every
+// QueryMetric are fetched together for each target, and the datapoints are
+// tagged with the target account and region. Other metrics are fetched one by one.
+// A target that fails, such as a role that cannot be assumed or a disabled region,
+// is skipped: the datapoints of the others are returned along with its error.
+func (s *Service) BatchDatapoints(ctx context.Context, metrics []costwatch.Metric, start time.Time, end time.Time) (map[string][]costwatch.Datapoint, error) {
+	out := make(map[string][]costwatch.Datapoint, len(metrics))
+
+	var qms []QueryMetric
+	for _, m := range metrics {
+		qm, ok := m.(QueryMetric)
+		if !ok {
+			dps, err := m.Datapoints(ctx, m.Label(), start, end)
+			if err != nil {
+				return nil, fmt.Errorf("%s.Datapoints: %w", m.Label(), err)
+			}
+			out[m.Label()] = dps
+			continue
+		}
+		qms = append(qms, qm)
+		out[m.Label()] = nil
+	}
+	if len(qms) == 0 {
+		return out, nil
+	}
+
+	var (
+		errs   []error
+		failed int
+	)
+targets:
+	for _, t := range s.targets {
+		var (
+			queries []Query
+			owners  []string // metric label per query
+		)
+		for _, qm := range qms {
+			qs, err := qm.Queries(ctx, t.Client)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.Queries (%s/%s): %w", qm.Label(), t.Account, t.Region, err))
+				failed++
+				continue targets
+			}
+			for _, q := range qs {
+				queries = append(queries, q)
+				owners = append(owners, qm.Label())
+			}
+		}
+
+		res, err := NewFetcher(t.Client).Fetch(ctx, queries, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fetch (%s/%s): %w", t.Account, t.Region, err))
+			failed++
+			continue
+		}
+		for i, dps := range res {
+			for _, dp := range dps {
+				dp.Account = t.Account
+				dp.Region = t.Region
+				out[owners[i]] = append(out[owners[i]], dp)
+			}
+		}
+	}
+	if failed == len(s.targets) {
+		return nil, errors.Join(errs...)
+	}
+
+	return out, errors.Join(errs...)
+}
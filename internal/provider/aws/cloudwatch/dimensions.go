@@ -0,0 +1,51 @@
+package cloudwatch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// DimensionSets lists the dimension combinations CloudWatch has for a metric that
+// have exactly the given dimension names. An empty value matches any value.
+func DimensionSets(ctx context.Context, client *cloudwatch.Client, namespace, metricName string, dims map[string]string) ([][]types.Dimension, error) {
+	names := make([]string, 0, len(dims))
+	for name := range dims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	filters := make([]types.DimensionFilter, 0, len(names))
+	for _, name := range names {
+		f := types.DimensionFilter{Name: aws.String(name)}
+		if v := dims[name]; v != "" {
+			f.Value = aws.String(v)
+		}
+		filters = append(filters, f)
+	}
+
+	var sets [][]types.Dimension
+	p := cloudwatch.NewListMetricsPaginator(client, &cloudwatch.ListMetricsInput{
+		MetricName: aws.String(metricName),
+		Namespace:  aws.String(namespace),
+		Dimensions: filters,
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cloudwatch.ListMetrics: %w", err)
+		}
+		for _, mt := range page.Metrics {
+			if len(mt.Dimensions) != len(dims) {
+				continue
+			}
+			sets = append(sets, mt.Dimensions)
+		}
+	}
+
+	return sets, nil
+}
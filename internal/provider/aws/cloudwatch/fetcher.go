@@ -0,0 +1,104 @@
+package cloudwatch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/tailbits/costwatch/internal/costwatch"
+)
+
+// maxQueriesPerRequest is the GetMetricData limit on metric queries per request.
+const maxQueriesPerRequest = 500
+
+// Query is a single CloudWatch metric series to fetch.
+type Query struct {
+	Namespace  string
+	MetricName string
+	Dimensions []types.Dimension
+	Stat       string
+	Period     int32
+}
+
+// Fetcher retrieves CloudWatch series with GetMetricData.
+type Fetcher struct {
+	client *cloudwatch.Client
+}
+
+func NewFetcher(client *cloudwatch.Client) *Fetcher {
+	return &Fetcher{client: client}
+}
+
+// Fetch retrieves the datapoints of every query between start and end. Queries are
+// batched up to 500 per GetMetricData request, and each request follows NextToken
+// until all datapoints are read. The result is indexed like queries, and each
+// datapoint carries the dimensions of its query.
+func (f *Fetcher) Fetch(ctx context.Context, queries []Query, start, end time.Time) ([][]costwatch.Datapoint, error) {
+	out := make([][]costwatch.Datapoint, len(queries))
+	for off := 0; off < len(queries); off += maxQueriesPerRequest {
+		batch := queries[off:min(off+maxQueriesPerRequest, len(queries))]
+
+		mdq := make([]types.MetricDataQuery, 0, len(batch))
+		for i, q := range batch {
+			mdq = append(mdq, types.MetricDataQuery{
+				Id: aws.String("q" + strconv.Itoa(i)),
+				MetricStat: &types.MetricStat{
+					Metric: &types.Metric{
+						Namespace:  aws.String(q.Namespace),
+						MetricName: aws.String(q.MetricName),
+						Dimensions: q.Dimensions,
+					},
+					Period: aws.Int32(q.Period),
+					Stat:   aws.String(q.Stat),
+				},
+				ReturnData: aws.Bool(true),
+			})
+		}
+
+		p := cloudwatch.NewGetMetricDataPaginator(f.client, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: mdq,
+			StartTime:         aws.Time(start),
+			EndTime:           aws.Time(end),
+			ScanBy:            types.ScanByTimestampAscending,
+		})
+		for p.HasMorePages() {
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("cloudwatch.GetMetricData: %w", err)
+			}
+			for _, r := range page.MetricDataResults {
+				i, err := strconv.Atoi(strings.TrimPrefix(aws.ToString(r.Id), "q"))
+				if err != nil || i < 0 || i >= len(batch) {
+					continue
+				}
+				labels := DimensionLabels(batch[i].Dimensions)
+				for j := range min(len(r.Timestamps), len(r.Values)) {
+					out[off+i] = append(out[off+i], costwatch.Datapoint{
+						Timestamp:  r.Timestamps[j],
+						Value:      r.Values[j],
+						Dimensions: labels,
+					})
+				}
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// DimensionLabels converts CloudWatch dimensions to datapoint dimensions.
+func DimensionLabels(dims []types.Dimension) map[string]string {
+	if len(dims) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(dims))
+	for _, d := range dims {
+		labels[aws.ToString(d.Name)] = aws.ToString(d.Value)
+	}
+	return labels
+}
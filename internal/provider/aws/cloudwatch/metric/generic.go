@@ -0,0 +1,220 @@
+package metric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/tailbits/costwatch/internal/costwatch"
+	cw "github.com/tailbits/costwatch/internal/provider/aws/cloudwatch"
+	"gopkg.in/yaml.v3"
+)
+
+var _ cw.QueryMetric = (*Generic)(nil)
+
+// Config defines a CloudWatch metric tracked without dedicated code.
+//
+// Dimensions pin the series to fetch; a "*" value fetches every value of that
+// dimension as a separate series. Price (in USD) is charged per UnitsPerPrice units.
+// Statistic is Sum or SampleCount, as usage adds the datapoints up.
+type Config struct {
+	Label         string            `json:"label" yaml:"label"`
+	Namespace     string            `json:"namespace" yaml:"namespace"`
+	MetricName    string            `json:"metric_name" yaml:"metric_name"`
+	Statistic     string            `json:"statistic" yaml:"statistic"`
+	Period        int32             `json:"period" yaml:"period"`
+	Dimensions    map[string]string `json:"dimensions" yaml:"dimensions"`
+	Price         float64           `json:"price" yaml:"price"`
+	UnitsPerPrice float64           `json:"units_per_price" yaml:"units_per_price"`
+}
+
+func (c *Config) validate() error {
+	if c.Namespace == "" || c.MetricName == "" {
+		return fmt.Errorf("namespace and metric_name are required")
+	}
+	if c.Label == "" {
+		c.Label = c.MetricName
+	}
+	if c.Statistic == "" {
+		c.Statistic = string(types.StatisticSum)
+	}
+	if c.Statistic != string(types.StatisticSum) && c.Statistic != string(types.StatisticSampleCount) {
+		return fmt.Errorf("%s: statistic must be Sum or SampleCount", c.Label)
+	}
+	if c.Period == 0 {
+		c.Period = 900
+	}
+	if c.Period < 60 || c.Period%60 != 0 {
+		return fmt.Errorf("%s: period must be a multiple of 60 seconds", c.Label)
+	}
+	if c.UnitsPerPrice <= 0 {
+		return fmt.Errorf("%s: units_per_price must be positive", c.Label)
+	}
+	return nil
+}
+
+// reservedLabels are the labels of the built-in CloudWatch metrics.
+var reservedLabels = map[string]bool{
+	IncomingBytesLabel: true,
+}
+
+// LoadConfigs reads metric definitions from a YAML or JSON file holding a list of Config.
+// Labels, which default to the metric name, must be unique and must not clash
+// with a built-in metric.
+func LoadConfigs(path string) ([]Config, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read metrics config: %w", err)
+	}
+
+	var doc struct {
+		Metrics []Config `json:"metrics" yaml:"metrics"`
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(buf, &doc)
+	} else {
+		err = yaml.Unmarshal(buf, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode metrics config %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(doc.Metrics))
+	for i := range doc.Metrics {
+		c := &doc.Metrics[i]
+		if err := c.validate(); err != nil {
+			return nil, fmt.Errorf("metric %d: %w", i, err)
+		}
+		if reservedLabels[c.Label] {
+			return nil, fmt.Errorf("metric %d: label %s is used by a built-in metric", i, c.Label)
+		}
+		if seen[c.Label] {
+			return nil, fmt.Errorf("metric %d: duplicate label %s", i, c.Label)
+		}
+		seen[c.Label] = true
+	}
+
+	return doc.Metrics, nil
+}
+
+// ConfigsFromEnv loads metric definitions from CLOUDWATCH_METRICS_PATH, if set.
+func ConfigsFromEnv() ([]Config, error) {
+	path := os.Getenv("CLOUDWATCH_METRICS_PATH")
+	if path == "" {
+		return nil, nil
+	}
+	return LoadConfigs(path)
+}
+
+// Generic is a CloudWatch metric constructed from a Config.
+type Generic struct {
+	log     *slog.Logger
+	client  *cloudwatch.Client
+	fetcher *cw.Fetcher
+	cfg     Config
+}
+
+func NewGeneric(log *slog.Logger, client *cloudwatch.Client, cfg Config) (*Generic, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid cloudwatch metric: %w", err)
+	}
+	return &Generic{
+		log:     log,
+		client:  client,
+		fetcher: cw.NewFetcher(client),
+		cfg:     cfg,
+	}, nil
+}
+
+func (m *Generic) Label() string {
+	return m.cfg.Label
+}
+
+// Price implements costwatch.Metric, in cents like the other metrics.
+func (m *Generic) Price() float64 {
+	return m.cfg.Price * 100
+}
+
+func (m *Generic) UnitsPerPrice() float64 {
+	return m.cfg.UnitsPerPrice
+}
+
+func (m *Generic) Datapoints(ctx context.Context, label string, start time.Time, end time.Time) ([]costwatch.Datapoint, error) {
+	qs, err := m.Queries(ctx, m.client)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.fetcher.Fetch(ctx, qs, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []costwatch.Datapoint
+	for _, dps := range res {
+		points = append(points, dps...)
+	}
+	m.log.Debug("fetched metric data", "points", len(points), "series", len(qs))
+
+	return points, nil
+}
+
+// Queries implements cloudwatch.QueryMetric. Without wildcard dimensions a
+// single series is fetched; otherwise one series per matching combination.
+func (m *Generic) Queries(ctx context.Context, client *cloudwatch.Client) ([]cw.Query, error) {
+	wildcard := false
+	names := make([]string, 0, len(m.cfg.Dimensions))
+	for name, v := range m.cfg.Dimensions {
+		names = append(names, name)
+		if v == "*" {
+			wildcard = true
+		}
+	}
+	sort.Strings(names)
+
+	if !wildcard {
+		dims := make([]types.Dimension, 0, len(names))
+		for _, name := range names {
+			dims = append(dims, types.Dimension{Name: aws.String(name), Value: aws.String(m.cfg.Dimensions[name])})
+		}
+		return []cw.Query{m.query(dims)}, nil
+	}
+
+	filter := make(map[string]string, len(m.cfg.Dimensions))
+	for name, v := range m.cfg.Dimensions {
+		if v == "*" {
+			v = ""
+		}
+		filter[name] = v
+	}
+	sets, err := cw.DimensionSets(ctx, client, m.cfg.Namespace, m.cfg.MetricName, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	qs := make([]cw.Query, 0, len(sets))
+	for _, dims := range sets {
+		qs = append(qs, m.query(dims))
+	}
+
+	return qs, nil
+}
+
+func (m *Generic) query(dims []types.Dimension) cw.Query {
+	return cw.Query{
+		Namespace:  m.cfg.Namespace,
+		MetricName: m.cfg.MetricName,
+		Dimensions: dims,
+		Stat:       m.cfg.Statistic,
+		Period:     m.cfg.Period,
+	}
+}
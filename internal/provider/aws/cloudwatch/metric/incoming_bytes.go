@@ -3,32 +3,38 @@ package metric
 import (
 	"context"
 	_ "embed"
-	"fmt"
 	"log/slog"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/tailbits/costwatch/internal/costwatch"
+	cw "github.com/tailbits/costwatch/internal/provider/aws/cloudwatch"
 )
 
 const (
+	IncomingBytesLabel         = "IncomingBytes"
 	IncomingBytesPrice         = 50
 	IncomingBytesUnitsPerPrice = 1e9
 )
 
-var _ costwatch.Metric = (*IncomingBytes)(nil)
+var _ cw.QueryMetric = (*IncomingBytes)(nil)
 
 type IncomingBytes struct {
-	log    *slog.Logger
-	client *cloudwatch.Client
+	log     *slog.Logger
+	client  *cloudwatch.Client
+	fetcher *cw.Fetcher
+	// dimensions lists the CloudWatch dimension names (e.g. LogGroupName) to break
+	// the metric down by. When empty, account-wide ingestion is fetched.
+	dimensions []string
 }
 
-func NewIncomingBytes(log *slog.Logger, client *cloudwatch.Client) *IncomingBytes {
+func NewIncomingBytes(log *slog.Logger, client *cloudwatch.Client, dimensions ...string) *IncomingBytes {
 	return &IncomingBytes{
-		log:    log,
-		client: client,
+		log:        log,
+		client:     client,
+		fetcher:    cw.NewFetcher(client),
+		dimensions: dimensions,
 	}
 }
 
@@ -38,32 +44,74 @@ func (m *IncomingBytes) UnitsPerPrice() float64 {
 }
 
 func (m *IncomingBytes) Datapoints(ctx context.Context, label string, start time.Time, end time.Time) ([]costwatch.Datapoint, error) {
-	data, err := m.client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-		MetricName: aws.String(m.Label()),
-		Namespace:  aws.String("AWS/Logs"),
-		Period:     aws.Int32(900),
-		StartTime:  &start,
-		EndTime:    &end,
-		Statistics: []types.Statistic{types.StatisticSum},
-	})
+	qs, err := m.Queries(ctx, m.client)
 	if err != nil {
-		return nil, fmt.Errorf("cloudwatch.GetMetricStatistics: %w", err)
+		return nil, err
 	}
-	m.log.Debug("fetched metric data", "points", len(data.Datapoints))
-
-	points := make([]costwatch.Datapoint, 0, len(data.Datapoints))
-	for _, result := range data.Datapoints {
-		points = append(points, costwatch.Datapoint{
-			Timestamp: *result.Timestamp,
-			Value:     *result.Sum,
-		})
+
+	res, err := m.fetcher.Fetch(ctx, qs, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []costwatch.Datapoint
+	for _, dps := range res {
+		points = append(points, dps...)
 	}
+	m.log.Debug("fetched metric data", "points", len(points), "series", len(qs))
 
 	return points, nil
 }
 
+// Queries implements cloudwatch.QueryMetric: a single account-wide series, or one
+// series per dimension combination when dimensions are configured.
+func (m *IncomingBytes) Queries(ctx context.Context, client *cloudwatch.Client) ([]cw.Query, error) {
+	if len(m.dimensions) == 0 {
+		return []cw.Query{m.query(nil)}, nil
+	}
+
+	sets, err := m.dimensionSets(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	qs := make([]cw.Query, 0, len(sets))
+	for _, dims := range sets {
+		qs = append(qs, m.query(dims))
+	}
+
+	return qs, nil
+}
+
+func (m *IncomingBytes) query(dims []types.Dimension) cw.Query {
+	return cw.Query{
+		Namespace:  "AWS/Logs",
+		MetricName: m.Label(),
+		Dimensions: dims,
+		Stat:       string(types.StatisticSum),
+		Period:     900,
+	}
+}
+
+// dimensionSets lists the dimension combinations CloudWatch has for this metric
+// that match exactly the configured dimension names.
+func (m *IncomingBytes) dimensionSets(ctx context.Context, client *cloudwatch.Client) ([][]types.Dimension, error) {
+	dims := make(map[string]string, len(m.dimensions))
+	for _, name := range m.dimensions {
+		dims[name] = ""
+	}
+
+	sets, err := cw.DimensionSets(ctx, client, "AWS/Logs", m.Label(), dims)
+	if err != nil {
+		return nil, err
+	}
+	m.log.Debug("listed metric dimensions", "sets", len(sets))
+
+	return sets, nil
+}
+
 func (m *IncomingBytes) Label() string {
-	return "IncomingBytes"
+	return IncomingBytesLabel
 }
 
 func (m *IncomingBytes) Price() float64 {
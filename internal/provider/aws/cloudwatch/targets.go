@@ -0,0 +1,85 @@
+package cloudwatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Target is an AWS account and region to collect CloudWatch metrics from.
+type Target struct {
+	Account string
+	Region  string
+	Client  *cloudwatch.Client
+}
+
+// NewTargets fans the base config out over every region and account. The
+// account of the base credentials is always included; each role ARN adds an
+// account accessed through STS AssumeRole. When regions is empty, the region
+// of the base config is used.
+func NewTargets(ctx context.Context, log *slog.Logger, cfg aws.Config, regions []string, roleARNs []string) ([]Target, error) {
+	if len(regions) == 0 {
+		regions = []string{cfg.Region}
+	}
+
+	type account struct {
+		id  string
+		cfg aws.Config
+	}
+
+	stsClient := sts.NewFromConfig(cfg)
+	base := account{cfg: cfg}
+	ident, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		// Untagged datapoints are still useful, so a missing identity is not fatal.
+		log.Warn("sts.GetCallerIdentity failed, collecting without account id", "error", err)
+	} else {
+		base.id = aws.ToString(ident.Account)
+	}
+	accounts := []account{base}
+
+	for _, roleARN := range roleARNs {
+		parsed, err := arn.Parse(roleARN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role arn %q: %w", roleARN, err)
+		}
+		acfg := cfg.Copy()
+		acfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "costwatch"
+		}))
+		accounts = append(accounts, account{id: parsed.AccountID, cfg: acfg})
+	}
+
+	targets := make([]Target, 0, len(accounts)*len(regions))
+	for _, a := range accounts {
+		for _, region := range regions {
+			rcfg := a.cfg.Copy()
+			rcfg.Region = region
+			targets = append(targets, Target{
+				Account: a.id,
+				Region:  region,
+				Client:  cloudwatch.NewFromConfig(rcfg),
+			})
+		}
+	}
+
+	return targets, nil
+}
+
+// SplitList splits a comma separated environment value, dropping empty items.
+func SplitList(v string) []string {
+	var out []string
+	for _, it := range strings.Split(v, ",") {
+		if it = strings.TrimSpace(it); it != "" {
+			out = append(out, it)
+		}
+	}
+	return out
+}
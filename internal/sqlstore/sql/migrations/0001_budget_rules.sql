@@ -0,0 +1,29 @@
+-- Alert rules are keyed by kind and period so a metric can have an hourly
+-- threshold and budgets for several periods at the same time.
+create table alert_rules_v2 (
+  service     text not null,
+  metric      text not null,
+  kind        text not null default 'threshold',
+  period      text not null default '',
+  threshold   real not null default 0,
+  amount      real not null default 0,
+  percentages text not null default '',
+  basis       text not null default '',
+  primary key (service, metric, kind, period)
+);
+
+insert into alert_rules_v2(service, metric, threshold)
+select service, metric, threshold from alert_rules;
+
+drop table alert_rules;
+
+alter table alert_rules_v2 rename to alert_rules;
+
+create table if not exists budget_state (
+  service       text not null,
+  metric        text not null,
+  period        text not null,
+  period_start  timestamp not null,
+  notified_pct  real not null,
+  primary key (service, metric, period)
+);
@@ -0,0 +1,3 @@
+alter table alert_rules add column method text not null default '';
+alter table alert_rules add column sensitivity real not null default 0;
+alter table alert_rules add column baseline_weeks integer not null default 0;
@@ -0,0 +1,3 @@
+alter table alert_rules add column percent real not null default 0;
+alter table alert_rules add column percentile text not null default '';
+alter table alert_rules add column baseline_days integer not null default 0;
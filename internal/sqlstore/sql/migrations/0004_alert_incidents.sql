@@ -0,0 +1,32 @@
+-- An incident is opened when a rule starts firing and resolved when it stops.
+-- Rules are identified like in alert_rules; window rules additionally keep one
+-- incident per dimension value.
+create table alert_incidents (
+  id           integer primary key autoincrement,
+  service      text not null,
+  metric       text not null,
+  kind         text not null,
+  period       text not null default '',
+  dimension    text not null default '',
+  status       text not null,
+  started_at   timestamp not null,
+  updated_at   timestamp not null,
+  resolved_at  timestamp,
+  peak_cost    real not null default 0,
+  overspend    real not null default 0,
+  summary      text not null default ''
+);
+
+create index alert_incidents_key on alert_incidents (service, metric, kind, period, dimension);
+create index alert_incidents_started on alert_incidents (started_at);
+
+create table alert_notifications (
+  id           integer primary key autoincrement,
+  incident_id  integer not null references alert_incidents (id),
+  sent_at      timestamp not null,
+  channel      text not null,
+  message      text not null,
+  error        text not null default ''
+);
+
+create index alert_notifications_incident on alert_notifications (incident_id);
@@ -0,0 +1,2 @@
+-- notified_at is the time of the last notification delivered for an incident.
+alter table alert_incidents add column notified_at timestamp;
@@ -0,0 +1,14 @@
+-- A silence mutes the notifications of the rules of a service/metric between
+-- starts_at and ends_at; an empty service or metric matches any.
+create table silences (
+  id          integer primary key autoincrement,
+  service     text not null default '',
+  metric      text not null default '',
+  starts_at   timestamp not null,
+  ends_at     timestamp not null,
+  reason      text not null default '',
+  author      text not null default '',
+  created_at  timestamp not null
+);
+
+create index silences_ends on silences (ends_at);
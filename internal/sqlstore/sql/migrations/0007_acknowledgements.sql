@@ -0,0 +1,5 @@
+-- renotify_interval is in seconds; 0 uses the default of one hour.
+alter table alert_rules add column renotify_interval integer not null default 0;
+
+alter table alert_incidents add column acked_at timestamp;
+alter table alert_incidents add column acked_by text not null default '';
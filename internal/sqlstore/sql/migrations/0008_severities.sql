@@ -0,0 +1,5 @@
+alter table alert_rules add column critical real not null default 0;
+-- escalate_after is in seconds; 0 disables escalation.
+alter table alert_rules add column escalate_after integer not null default 0;
+
+alter table alert_incidents add column severity text not null default 'warning';
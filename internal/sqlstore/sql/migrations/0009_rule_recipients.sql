@@ -0,0 +1,2 @@
+-- recipients is a comma separated list of the email addresses of a rule.
+alter table alert_rules add column recipients text not null default '';
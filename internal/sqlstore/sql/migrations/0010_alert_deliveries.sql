@@ -0,0 +1,19 @@
+-- The outbox of alert notifications. A delivery is pending until it is
+-- delivered, or failed once it ran out of retries; event is the JSON of the
+-- alert. At most one delivery of an alert (dedup_key) per channel is pending.
+create table alert_deliveries (
+  id               integer primary key autoincrement,
+  incident_id      integer not null default 0,
+  channel          text not null,
+  dedup_key        text not null,
+  event            text not null,
+  status           text not null,
+  attempts         integer not null default 0,
+  last_error       text not null default '',
+  created_at       timestamp not null,
+  next_attempt_at  timestamp,
+  delivered_at     timestamp
+);
+
+create unique index alert_deliveries_pending on alert_deliveries (channel, dedup_key) where status = 'pending';
+create index alert_deliveries_status on alert_deliveries (status, next_attempt_at);
@@ -0,0 +1,12 @@
+-- A notification channel is a named notifier; config is its JSON
+-- configuration, which depends on its type (webhook, pagerduty or email).
+create table notification_channels (
+  name        text primary key,
+  type        text not null,
+  config      text not null,
+  created_at  timestamp not null,
+  updated_at  timestamp not null
+);
+
+-- channels is a comma separated list of the notification channels of a rule.
+alter table alert_rules add column channels text not null default '';
@@ -0,0 +1,25 @@
+-- The last notification of each rule and dimension value, used when
+-- incidents are not tracked. It replaces sync_state.last_notified, which was
+-- shared by every rule of a service/metric.
+create table alert_last_notified (
+  service        text not null,
+  metric         text not null,
+  kind           text not null,
+  period         text not null default '',
+  dimension      text not null default '',
+  last_notified  timestamp not null,
+  primary key (service, metric, kind, period, dimension)
+);
+
+-- The old value applied to every rule of a service/metric, so it is carried
+-- over to each kind of rule notified from windows.
+insert into alert_last_notified(service, metric, kind, last_notified)
+select s.service, s.metric, k.kind, s.last_notified
+from sync_state s
+cross join (
+  select 'threshold' as kind
+  union all select 'anomaly'
+  union all select 'baseline'
+  union all select 'change'
+) k
+where s.last_notified is not null;
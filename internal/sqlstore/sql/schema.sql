@@ -1,3 +1,4 @@
+-- Baseline schema. Later changes are applied by sql/migrations in order.
 create table if not exists sync_state (
   service        text not null,
   metric         text not null,
@@ -3,10 +3,13 @@ package sqlstore
 import (
 	"context"
 	"database/sql"
-	_ "embed"
+	"embed"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -54,9 +57,57 @@ func Open() (*Store, error) {
 //go:embed sql/schema.sql
 var schemaSQL string
 
+// migrations upgrade databases created by earlier versions of the schema. They
+// are applied in lexical order, each at most once: the number of applied
+// migrations is kept in PRAGMA user_version.
+//
+//go:embed sql/migrations/*.sql
+var migrationsFS embed.FS
+
 func ensureSchema(db *sql.DB) error {
-	_, err := db.Exec(schemaSQL)
-	return err
+	if _, err := db.Exec(schemaSQL); err != nil {
+		return err
+	}
+	return migrate(db)
+}
+
+func migrate(db *sql.DB) error {
+	names, err := fs.Glob(migrationsFS, "sql/migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read user_version: %w", err)
+	}
+
+	for i := version; i < len(names); i++ {
+		stmt, err := migrationsFS.ReadFile(names[i])
+		if err != nil {
+			return fmt.Errorf("read %s: %w", names[i], err)
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(string(stmt)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("exec %s: %w", names[i], err)
+		}
+		// PRAGMA does not accept bound parameters.
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("set user_version: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (s *Store) Close() error {
@@ -15,6 +15,10 @@ import (
 	"github.com/tailbits/costwatch/internal/webctx"
 )
 
+// ErrNotFound is returned by handlers when the requested resource does not
+// exist. It is answered with a 404.
+var ErrNotFound = errors.New("not found")
+
 type APISchema struct {
 	InputDoc  model.Entity
 	OutputDoc model.Entity
@@ -99,6 +103,11 @@ func (a *Server) NewRoute(method string, group string, path string, handler maso
 				return
 			}
 
+			if errors.Is(err, ErrNotFound) {
+				http.Error(w, "Not Found", http.StatusNotFound)
+				return
+			}
+
 			a.log.Error("handler error", "error", err)
 			// You might want to handle the error here, e.g., write an error response
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)